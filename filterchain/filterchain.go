@@ -0,0 +1,453 @@
+// Package filterchain simulates NSONE filter chains: given a record's
+// answers, their metadata and its filters, it works out which answers a
+// resolver making a request from a given place would be sent.
+//
+// Only the common filters are implemented; see Simulate. The order the
+// shuffling filters produce depends on the *rand.Rand passed in, so a
+// simulation can be repeated by seeding it the same way.
+package filterchain
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	nsone "gopkg.in/sarguru/ns1-go.v18"
+)
+
+// Record is what a simulation needs of a record: its answers, its filter
+// chain, and the metadata of the record and of its regions.
+type Record struct {
+	Answers    []nsone.Answer
+	Filters    []nsone.Filter
+	Meta       map[string]interface{}
+	RegionMeta map[string]map[string]interface{}
+}
+
+// Request describes the resolver a simulated query comes from. Empty fields
+// are unknown, and filters that need them leave the answers as they are.
+type Request struct {
+	Country    string
+	USState    string
+	CAProvince string
+	GeoRegion  string
+	// HasLocation is set if Latitude and Longitude are known.
+	HasLocation bool
+	Latitude    float64
+	Longitude   float64
+	IP          net.IP
+	ASN         int
+}
+
+// Result is the outcome of a simulation.
+type Result struct {
+	// Answers are the answers the filter chain emits, in order.
+	Answers []nsone.Answer
+	// Skipped are the filters in the chain that the simulator does not
+	// implement, and so left the answers as they were.
+	Skipped []string
+}
+
+// answer is an answer with its effective metadata: the answer's own, over
+// its region's, over the record's, with feed pointers replaced by the
+// values of the feeds.
+type answer struct {
+	nsone.Answer
+	meta map[string]interface{}
+}
+
+// filterFunc applies a filter, with its config, to the answers.
+type filterFunc func(answers []answer, config map[string]interface{}, req Request, rnd *rand.Rand) []answer
+
+var filters = map[string]filterFunc{
+	"up":                  filterUp,
+	"geotarget_country":   filterGeotargetCountry,
+	"geotarget_regional":  filterGeotargetRegional,
+	"geotarget_latlong":   filterGeotargetLatlong,
+	"geofence_country":    filterGeofenceCountry,
+	"geofence_regional":   filterGeofenceRegional,
+	"netfence_asn":        filterNetfenceASN,
+	"netfence_prefix":     filterNetfencePrefix,
+	"weighted_shuffle":    filterWeightedShuffle,
+	"shuffle":             filterShuffle,
+	"select_first_n":      filterSelectFirstN,
+	"select_first_region": filterSelectFirstRegion,
+	"priority":            filterPriority,
+}
+
+// Simulate runs the filter chain of r for a query from req. feeds holds the
+// current values of the data feeds answers' metadata may point to, by feed
+// id; metadata pointing to a feed missing from it is taken as unset.
+// Disabled filters are skipped, as the API does.
+func Simulate(r *Record, req Request, feeds map[string]interface{}, rnd *rand.Rand) *Result {
+	answers := make([]answer, len(r.Answers))
+	for i, a := range r.Answers {
+		meta := make(map[string]interface{})
+		mergeMeta(meta, r.Meta, feeds)
+		if a.Region != "" {
+			mergeMeta(meta, r.RegionMeta[a.Region], feeds)
+		}
+		mergeMeta(meta, a.Meta, feeds)
+		answers[i] = answer{Answer: a, meta: meta}
+	}
+
+	result := &Result{}
+	for _, f := range r.Filters {
+		if f.Disabled {
+			continue
+		}
+		fn, ok := filters[f.Filter]
+		if !ok {
+			result.Skipped = append(result.Skipped, f.Filter)
+			continue
+		}
+		answers = fn(answers, f.Config, req, rnd)
+	}
+
+	result.Answers = make([]nsone.Answer, len(answers))
+	for i, a := range answers {
+		result.Answers[i] = a.Answer
+	}
+	return result
+}
+
+func mergeMeta(dst, src map[string]interface{}, feeds map[string]interface{}) {
+	for k, v := range src {
+		if feed, ok := v.(map[string]interface{}); ok {
+			id, _ := feed["feed"].(string)
+			fv, ok := feeds[id]
+			if !ok {
+				delete(dst, k)
+				continue
+			}
+			v = fv
+		}
+		dst[k] = v
+	}
+}
+
+func filterUp(answers []answer, config map[string]interface{}, req Request, rnd *rand.Rand) []answer {
+	return keep(answers, func(a answer) bool {
+		up, ok := metaBool(a.meta, "up")
+		return !ok || up
+	})
+}
+
+// filterGeotargetCountry moves the answers in the requester's US state or
+// Canadian province, then those in their country, to the front.
+func filterGeotargetCountry(answers []answer, config map[string]interface{}, req Request, rnd *rand.Rand) []answer {
+	if req.Country == "" {
+		return answers
+	}
+	return rank(answers, func(a answer) int {
+		switch {
+		case req.USState != "" && metaContains(a.meta, "us_state", req.USState):
+			return 2
+		case req.CAProvince != "" && metaContains(a.meta, "ca_province", req.CAProvince):
+			return 2
+		case metaContains(a.meta, "country", req.Country):
+			return 1
+		}
+		return 0
+	})
+}
+
+// filterGeotargetRegional moves the answers in the requester's georegion to
+// the front.
+func filterGeotargetRegional(answers []answer, config map[string]interface{}, req Request, rnd *rand.Rand) []answer {
+	if req.GeoRegion == "" {
+		return answers
+	}
+	return rank(answers, func(a answer) int {
+		if metaContains(a.meta, "georegion", req.GeoRegion) {
+			return 1
+		}
+		return 0
+	})
+}
+
+// filterGeotargetLatlong orders the answers by their distance from the
+// requester, nearest first. Answers without a location go last.
+func filterGeotargetLatlong(answers []answer, config map[string]interface{}, req Request, rnd *rand.Rand) []answer {
+	if !req.HasLocation {
+		return answers
+	}
+	distance := func(a answer) float64 {
+		lat, ok1 := metaFloat(a.meta, "latitude")
+		long, ok2 := metaFloat(a.meta, "longitude")
+		if !ok1 || !ok2 {
+			return math.Inf(1)
+		}
+		return greatCircle(req.Latitude, req.Longitude, lat, long)
+	}
+	sorted := append([]answer(nil), answers...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return distance(sorted[i]) < distance(sorted[j])
+	})
+	return sorted
+}
+
+// filterGeofenceCountry removes the answers outside of the requester's
+// country (or US state / Canadian province, where the answer has them).
+// Answers with no location are kept, unless remove_no_location is set.
+func filterGeofenceCountry(answers []answer, config map[string]interface{}, req Request, rnd *rand.Rand) []answer {
+	removeNoLocation := configBool(config, "remove_no_location")
+	return keep(answers, func(a answer) bool {
+		_, hasCountry := a.meta["country"]
+		_, hasState := a.meta["us_state"]
+		_, hasProvince := a.meta["ca_province"]
+		if !hasCountry && !hasState && !hasProvince {
+			return !removeNoLocation
+		}
+		if hasState && req.USState != "" && metaContains(a.meta, "us_state", req.USState) {
+			return true
+		}
+		if hasProvince && req.CAProvince != "" && metaContains(a.meta, "ca_province", req.CAProvince) {
+			return true
+		}
+		return req.Country != "" && metaContains(a.meta, "country", req.Country)
+	})
+}
+
+// filterGeofenceRegional removes the answers outside of the requester's
+// georegion. Answers with no georegion are kept, unless remove_no_georegion
+// is set.
+func filterGeofenceRegional(answers []answer, config map[string]interface{}, req Request, rnd *rand.Rand) []answer {
+	removeNoGeoregion := configBool(config, "remove_no_georegion")
+	return keep(answers, func(a answer) bool {
+		if _, ok := a.meta["georegion"]; !ok {
+			return !removeNoGeoregion
+		}
+		return req.GeoRegion != "" && metaContains(a.meta, "georegion", req.GeoRegion)
+	})
+}
+
+// filterNetfenceASN removes the answers whose asn list does not include the
+// requester's. Answers with no asn are kept, unless remove_no_asn is set.
+func filterNetfenceASN(answers []answer, config map[string]interface{}, req Request, rnd *rand.Rand) []answer {
+	removeNoASN := configBool(config, "remove_no_asn")
+	return keep(answers, func(a answer) bool {
+		if _, ok := a.meta["asn"]; !ok {
+			return !removeNoASN
+		}
+		return req.ASN != 0 && metaContains(a.meta, "asn", strconv.Itoa(req.ASN))
+	})
+}
+
+// filterNetfencePrefix removes the answers whose ip_prefixes do not include
+// the requester's IP. Answers with no ip_prefixes are kept, unless
+// remove_no_ip_prefixes is set.
+func filterNetfencePrefix(answers []answer, config map[string]interface{}, req Request, rnd *rand.Rand) []answer {
+	removeNoPrefixes := configBool(config, "remove_no_ip_prefixes")
+	return keep(answers, func(a answer) bool {
+		prefixes, ok := metaStrings(a.meta, "ip_prefixes")
+		if !ok {
+			return !removeNoPrefixes
+		}
+		if req.IP == nil {
+			return false
+		}
+		for _, p := range prefixes {
+			if _, n, err := net.ParseCIDR(p); err == nil && n.Contains(req.IP) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// filterWeightedShuffle orders the answers randomly, each next answer being
+// picked with a probability proportional to its weight (1 if unset).
+func filterWeightedShuffle(answers []answer, config map[string]interface{}, req Request, rnd *rand.Rand) []answer {
+	left := append([]answer(nil), answers...)
+	shuffled := make([]answer, 0, len(answers))
+	for len(left) > 0 {
+		total := 0.0
+		for _, a := range left {
+			total += weight(a)
+		}
+		i := len(left) - 1
+		if total > 0 {
+			pick := rnd.Float64() * total
+			for j, a := range left {
+				pick -= weight(a)
+				if pick < 0 {
+					i = j
+					break
+				}
+			}
+		} else {
+			i = rnd.Intn(len(left))
+		}
+		shuffled = append(shuffled, left[i])
+		left = append(left[:i], left[i+1:]...)
+	}
+	return shuffled
+}
+
+func weight(a answer) float64 {
+	if w, ok := metaFloat(a.meta, "weight"); ok {
+		return w
+	}
+	return 1
+}
+
+func filterShuffle(answers []answer, config map[string]interface{}, req Request, rnd *rand.Rand) []answer {
+	shuffled := append([]answer(nil), answers...)
+	rnd.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return shuffled
+}
+
+// filterSelectFirstN keeps the first N answers, 1 if N is not configured.
+func filterSelectFirstN(answers []answer, config map[string]interface{}, req Request, rnd *rand.Rand) []answer {
+	n := 1
+	if v, ok := configFloat(config, "N"); ok {
+		n = int(v)
+	}
+	if n < len(answers) {
+		return answers[:n]
+	}
+	return answers
+}
+
+// filterSelectFirstRegion keeps the answers in the same region as the first
+// answer.
+func filterSelectFirstRegion(answers []answer, config map[string]interface{}, req Request, rnd *rand.Rand) []answer {
+	if len(answers) == 0 {
+		return answers
+	}
+	region := answers[0].Region
+	return keep(answers, func(a answer) bool {
+		return a.Region == region
+	})
+}
+
+// filterPriority keeps the answers with the lowest priority value. Answers
+// with no priority come after those with one.
+func filterPriority(answers []answer, config map[string]interface{}, req Request, rnd *rand.Rand) []answer {
+	priority := func(a answer) float64 {
+		if p, ok := metaFloat(a.meta, "priority"); ok {
+			return p
+		}
+		return math.Inf(1)
+	}
+	lowest := math.Inf(1)
+	for _, a := range answers {
+		lowest = math.Min(lowest, priority(a))
+	}
+	return keep(answers, func(a answer) bool {
+		return priority(a) == lowest
+	})
+}
+
+func keep(answers []answer, f func(answer) bool) []answer {
+	kept := make([]answer, 0, len(answers))
+	for _, a := range answers {
+		if f(a) {
+			kept = append(kept, a)
+		}
+	}
+	return kept
+}
+
+// rank stably sorts the answers by f, highest first.
+func rank(answers []answer, f func(answer) int) []answer {
+	sorted := append([]answer(nil), answers...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return f(sorted[i]) > f(sorted[j])
+	})
+	return sorted
+}
+
+// greatCircle returns the distance in km between two points, by the
+// haversine formula.
+func greatCircle(lat1, long1, lat2, long2 float64) float64 {
+	const earthRadius = 6371
+	rad := math.Pi / 180
+	dlat := (lat2 - lat1) * rad
+	dlong := (long2 - long1) * rad
+	h := math.Sin(dlat/2)*math.Sin(dlat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dlong/2)*math.Sin(dlong/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
+
+// The meta... and config... helpers read values as the API returns them,
+// JSON decoded, as well as the strings data feeds and the provider's config
+// maps hold.
+
+func metaBool(m map[string]interface{}, k string) (bool, bool) {
+	switch v := m[k].(type) {
+	case bool:
+		return v, true
+	case float64:
+		return v != 0, true
+	case int:
+		return v != 0, true
+	case string:
+		b, err := strconv.ParseBool(v)
+		return b, err == nil
+	}
+	return false, false
+}
+
+func metaFloat(m map[string]interface{}, k string) (float64, bool) {
+	switch v := m[k].(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func metaStrings(m map[string]interface{}, k string) ([]string, bool) {
+	switch v := m[k].(type) {
+	case []interface{}:
+		s := make([]string, len(v))
+		for i, e := range v {
+			s[i] = fmt.Sprintf("%v", e)
+		}
+		return s, true
+	case []string:
+		return v, true
+	case string:
+		s := strings.Split(v, ",")
+		for i := range s {
+			s[i] = strings.TrimSpace(s[i])
+		}
+		return s, true
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}, true
+	case int:
+		return []string{strconv.Itoa(v)}, true
+	}
+	return nil, false
+}
+
+func metaContains(m map[string]interface{}, k, value string) bool {
+	l, _ := metaStrings(m, k)
+	for _, e := range l {
+		if strings.EqualFold(e, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func configBool(config map[string]interface{}, k string) bool {
+	b, _ := metaBool(config, k)
+	return b
+}
+
+func configFloat(config map[string]interface{}, k string) (float64, bool) {
+	return metaFloat(config, k)
+}
@@ -0,0 +1,165 @@
+package filterchain
+
+import (
+	"math/rand"
+	"net"
+	"reflect"
+	"testing"
+
+	nsone "gopkg.in/sarguru/ns1-go.v18"
+)
+
+func testAnswer(rdata, region string, meta map[string]interface{}) nsone.Answer {
+	return nsone.Answer{Answer: []string{rdata}, Region: region, Meta: meta}
+}
+
+func answerStrings(answers []nsone.Answer) []string {
+	s := make([]string, len(answers))
+	for i, a := range answers {
+		s[i] = a.Answer[0]
+	}
+	return s
+}
+
+func testSimulate(t *testing.T, r *Record, req Request, feeds map[string]interface{}, expected ...string) {
+	t.Helper()
+	result := Simulate(r, req, feeds, rand.New(rand.NewSource(1)))
+	if expected == nil {
+		expected = []string{}
+	}
+	if got := answerStrings(result.Answers); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestUpWithFeeds(t *testing.T) {
+	r := &Record{
+		Answers: []nsone.Answer{
+			testAnswer("1.1.1.1", "", map[string]interface{}{"up": map[string]interface{}{"feed": "dc1"}}),
+			testAnswer("2.2.2.2", "", map[string]interface{}{"up": map[string]interface{}{"feed": "dc2"}}),
+			testAnswer("3.3.3.3", "", map[string]interface{}{"up": false}),
+		},
+		Filters: []nsone.Filter{{Filter: "up"}},
+	}
+	testSimulate(t, r, Request{}, map[string]interface{}{"dc1": "false", "dc2": true}, "2.2.2.2")
+	// A feed with no value leaves up unset, which counts as up.
+	testSimulate(t, r, Request{}, nil, "1.1.1.1", "2.2.2.2")
+}
+
+func TestRegionMetaIsInherited(t *testing.T) {
+	r := &Record{
+		Answers: []nsone.Answer{
+			testAnswer("1.1.1.1", "us", nil),
+			testAnswer("2.2.2.2", "eu", nil),
+			testAnswer("3.3.3.3", "eu", map[string]interface{}{"country": []interface{}{"FR"}}),
+		},
+		Filters: []nsone.Filter{{Filter: "geofence_country"}},
+		RegionMeta: map[string]map[string]interface{}{
+			"us": {"country": []interface{}{"US"}},
+			"eu": {"country": []interface{}{"DE", "FR"}},
+		},
+	}
+	testSimulate(t, r, Request{Country: "DE"}, nil, "2.2.2.2")
+	testSimulate(t, r, Request{Country: "FR"}, nil, "2.2.2.2", "3.3.3.3")
+}
+
+func TestGeotargetCountry(t *testing.T) {
+	r := &Record{
+		Answers: []nsone.Answer{
+			testAnswer("1.1.1.1", "", map[string]interface{}{"country": []interface{}{"GB"}}),
+			testAnswer("2.2.2.2", "", map[string]interface{}{"country": []interface{}{"US"}}),
+			testAnswer("3.3.3.3", "", map[string]interface{}{"us_state": []interface{}{"NY"}}),
+		},
+		Filters: []nsone.Filter{{Filter: "geotarget_country"}, {Filter: "select_first_n", Config: map[string]interface{}{"N": float64(2)}}},
+	}
+	testSimulate(t, r, Request{Country: "US", USState: "NY"}, nil, "3.3.3.3", "2.2.2.2")
+	testSimulate(t, r, Request{Country: "GB"}, nil, "1.1.1.1", "2.2.2.2")
+}
+
+func TestGeotargetRegionalAndLatlong(t *testing.T) {
+	r := &Record{
+		Answers: []nsone.Answer{
+			testAnswer("sydney", "", map[string]interface{}{"georegion": []interface{}{"ASIAPAC"}, "latitude": -33.87, "longitude": 151.21}),
+			testAnswer("santiago", "", map[string]interface{}{"georegion": []interface{}{"SOUTH-AMERICA"}, "latitude": -33.45, "longitude": -70.67}),
+			testAnswer("nowhere", "", nil),
+			testAnswer("london", "", map[string]interface{}{"georegion": []interface{}{"EUROPE"}, "latitude": 51.51, "longitude": -0.13}),
+		},
+		Filters: []nsone.Filter{{Filter: "geotarget_regional"}},
+	}
+	testSimulate(t, r, Request{GeoRegion: "EUROPE"}, nil, "london", "sydney", "santiago", "nowhere")
+
+	r.Filters = []nsone.Filter{{Filter: "geotarget_latlong"}}
+	// Buenos Aires
+	testSimulate(t, r, Request{HasLocation: true, Latitude: -34.6, Longitude: -58.38}, nil, "santiago", "london", "sydney", "nowhere")
+}
+
+func TestNetfence(t *testing.T) {
+	r := &Record{
+		Answers: []nsone.Answer{
+			testAnswer("internal", "", map[string]interface{}{"ip_prefixes": []interface{}{"10.0.0.0/8"}, "asn": []interface{}{float64(64512)}}),
+			testAnswer("public", "", nil),
+		},
+		Filters: []nsone.Filter{{Filter: "netfence_prefix"}},
+	}
+	testSimulate(t, r, Request{IP: net.ParseIP("10.1.2.3")}, nil, "internal", "public")
+	testSimulate(t, r, Request{IP: net.ParseIP("192.0.2.1")}, nil, "public")
+
+	r.Filters = []nsone.Filter{{Filter: "netfence_asn", Config: map[string]interface{}{"remove_no_asn": true}}}
+	testSimulate(t, r, Request{ASN: 64512}, nil, "internal")
+	testSimulate(t, r, Request{ASN: 3320}, nil)
+}
+
+func TestPriority(t *testing.T) {
+	r := &Record{
+		Answers: []nsone.Answer{
+			testAnswer("backup", "", map[string]interface{}{"priority": float64(2)}),
+			testAnswer("primary1", "", map[string]interface{}{"priority": float64(1), "up": map[string]interface{}{"feed": "p1"}}),
+			testAnswer("primary2", "", map[string]interface{}{"priority": float64(1)}),
+		},
+		Filters: []nsone.Filter{{Filter: "up"}, {Filter: "priority"}},
+	}
+	testSimulate(t, r, Request{}, nil, "primary1", "primary2")
+	r.Answers[2].Meta["up"] = false
+	testSimulate(t, r, Request{}, map[string]interface{}{"p1": "0"}, "backup")
+}
+
+func TestWeightedShuffleIsSeededAndWeighted(t *testing.T) {
+	r := &Record{
+		Answers: []nsone.Answer{
+			testAnswer("heavy", "", map[string]interface{}{"weight": float64(99)}),
+			testAnswer("light", "", map[string]interface{}{"weight": 0.5}),
+		},
+		Filters: []nsone.Filter{{Filter: "weighted_shuffle"}, {Filter: "select_first_n"}},
+	}
+	heavy := 0
+	for seed := int64(0); seed < 200; seed++ {
+		first := Simulate(r, Request{}, nil, rand.New(rand.NewSource(seed)))
+		again := Simulate(r, Request{}, nil, rand.New(rand.NewSource(seed)))
+		if !reflect.DeepEqual(first.Answers, again.Answers) {
+			t.Fatalf("seed %d: simulation is not repeatable", seed)
+		}
+		if first.Answers[0].Answer[0] == "heavy" {
+			heavy++
+		}
+	}
+	if heavy < 190 {
+		t.Fatalf("expected the heavy answer first nearly every time, got %d/200", heavy)
+	}
+}
+
+func TestSkippedAndDisabledFilters(t *testing.T) {
+	r := &Record{
+		Answers: []nsone.Answer{
+			testAnswer("1.1.1.1", "", map[string]interface{}{"up": false}),
+			testAnswer("2.2.2.2", "", nil),
+		},
+		Filters: []nsone.Filter{{Filter: "up", Disabled: true}, {Filter: "shed_load"}},
+	}
+	result := Simulate(r, Request{}, nil, rand.New(rand.NewSource(1)))
+	if got := answerStrings(result.Answers); !reflect.DeepEqual(got, []string{"1.1.1.1", "2.2.2.2"}) {
+		t.Fatalf("bad answers: %v", got)
+	}
+	if !reflect.DeepEqual(result.Skipped, []string{"shed_load"}) {
+		t.Fatalf("bad skipped filters: %v", result.Skipped)
+	}
+}
@@ -0,0 +1,374 @@
+package nsone
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	nsone "gopkg.in/sarguru/ns1-go.v18"
+)
+
+// defaultEndpoint is the public NS1 API, used unless the provider is
+// configured with another endpoint.
+const defaultEndpoint = "https://api.nsone.net/v1/"
+
+// Client talks to the NS1 API on behalf of the provider. It has the same
+// methods and uses the same types as nsone.APIClient, but lets the API
+// endpoint and the HTTP transport (TLS, proxy) be configured, which the
+// ns1-go client hard-codes. Unlike nsone.APIClient, getting an object that
+// does not exist returns a *NotFoundError rather than an empty object.
+type Client struct {
+	ApiKey       string
+	Endpoint     string
+	httpClient   *http.Client
+	limiter      *rateLimiter
+	retryMax     int
+	retryMaxWait time.Duration
+}
+
+// NewClient takes an API key, an endpoint and the *http.Client to use, and
+// creates a *Client. An empty endpoint means the public NS1 API.
+func NewClient(k string, endpoint string, httpClient *http.Client) *Client {
+	if endpoint == "" {
+		endpoint = defaultEndpoint
+	}
+	if !strings.HasSuffix(endpoint, "/") {
+		endpoint = endpoint + "/"
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	limiter, _ := newRateLimiter(rateLimitNone, 0)
+	return &Client{
+		ApiKey:     k,
+		Endpoint:   endpoint,
+		httpClient: httpClient,
+		limiter:    limiter,
+	}
+}
+
+// SetRateLimit makes the client share one rate limiter, using the given
+// strategy and allowing at most maxParallel requests in flight (0 for no
+// cap), between every request it sends.
+func (c *Client) SetRateLimit(strategy string, maxParallel int) error {
+	limiter, err := newRateLimiter(strategy, maxParallel)
+	if err != nil {
+		return err
+	}
+	c.limiter = limiter
+	return nil
+}
+
+// SetRetry makes the client retry requests that fail with a 429, a 5xx or
+// a network error up to retryMax times, backing off exponentially but never
+// waiting more than retryMaxWait between attempts. See shouldRetry for
+// which requests are safe to retry.
+func (c *Client) SetRetry(retryMax int, retryMaxWait time.Duration) {
+	c.retryMax = retryMax
+	c.retryMaxWait = retryMaxWait
+}
+
+func (c *Client) url(format string, a ...interface{}) string {
+	return c.Endpoint + fmt.Sprintf(format, a...)
+}
+
+func (c *Client) doHTTP(method string, uri string, rbody []byte) ([]byte, int, error) {
+	req, err := http.NewRequest(method, uri, bytes.NewReader(rbody))
+	if err != nil {
+		return nil, 510, err
+	}
+	req.Header.Add("X-NSONE-Key", c.ApiKey)
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 {
+			req.Body, _ = req.GetBody()
+		}
+		body, status, header, err := c.doHTTPOnce(req)
+		if method == "DELETE" && attempt > 1 && isNotFound(err) {
+			// An earlier attempt may have deleted it before failing.
+			log.Printf("[WARN] nsone: %s %s: not found on attempt %d, taking it as deleted", method, uri, attempt)
+			return body, status, nil
+		}
+		if attempt > c.retryMax || !shouldRetry(method, status, header, err) {
+			if err != nil && attempt > 1 {
+				err = fmt.Errorf("%w (giving up after %d attempts)", err, attempt)
+			}
+			return body, status, err
+		}
+		wait := retryWait(attempt, c.retryMaxWait, header)
+		log.Printf("[WARN] nsone: %s %s failed: %s; retrying in %s (attempt %d of %d)",
+			method, uri, err, wait, attempt+1, c.retryMax+1)
+		time.Sleep(wait)
+	}
+}
+
+// doHTTPOnce sends req a single time. The returned header is nil if no
+// response was received.
+func (c *Client) doHTTPOnce(req *http.Request) ([]byte, int, http.Header, error) {
+	var body []byte
+	c.limiter.acquire()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.limiter.release(nsone.RateLimit{}, false)
+		return body, 510, nil, err
+	}
+	body, _ = ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	c.limiter.release(rateLimitFromHeader(resp.Header))
+	if resp.StatusCode != 200 {
+		return body, resp.StatusCode, resp.Header, newAPIError(resp.StatusCode, resp.Status, req.URL.Path, body)
+	}
+	return body, resp.StatusCode, resp.Header, nil
+}
+
+func rateLimitFromHeader(h http.Header) (nsone.RateLimit, bool) {
+	limit, err := strconv.Atoi(h.Get("X-Ratelimit-Limit"))
+	if err != nil {
+		return nsone.RateLimit{}, false
+	}
+	remaining, err := strconv.Atoi(h.Get("X-Ratelimit-Remaining"))
+	if err != nil {
+		return nsone.RateLimit{}, false
+	}
+	period, err := strconv.Atoi(h.Get("X-Ratelimit-Period"))
+	if err != nil {
+		return nsone.RateLimit{}, false
+	}
+	return nsone.RateLimit{
+		Limit:     limit,
+		Remaining: remaining,
+		Period:    period,
+	}, true
+}
+
+func (c *Client) doHTTPUnmarshal(method string, uri string, rbody []byte, unpackInto interface{}) (int, error) {
+	body, status, err := c.doHTTP(method, uri, rbody)
+	if err != nil {
+		return status, err
+	}
+	return status, json.Unmarshal(body, unpackInto)
+}
+
+func (c *Client) doHTTPBoth(method string, uri string, s interface{}) error {
+	rbody, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	_, err = c.doHTTPUnmarshal(method, uri, rbody, s)
+	return err
+}
+
+func (c *Client) doHTTPDelete(uri string) error {
+	_, _, err := c.doHTTP("DELETE", uri, nil)
+	return err
+}
+
+// GetZones returns all active zones and basic zone configuration details for each
+func (c *Client) GetZones() ([]nsone.Zone, error) {
+	var zl []nsone.Zone
+	_, err := c.doHTTPUnmarshal("GET", c.url("zones"), nil, &zl)
+	return zl, err
+}
+
+// GetZone takes a zone and returns a single active zone and its basic configuration details
+func (c *Client) GetZone(zone string) (*Zone, error) {
+	z := NewZone(zone)
+	_, err := c.doHTTPUnmarshal("GET", c.url("zones/%s", z.Zone.Zone), nil, z)
+	return z, err
+}
+
+// DeleteZone takes a zone and destroys an existing DNS zone and all records in the zone
+func (c *Client) DeleteZone(zone string) error {
+	return c.doHTTPDelete(c.url("zones/%s", zone))
+}
+
+// CreateZone takes a *Zone and creates a new DNS zone
+func (c *Client) CreateZone(z *Zone) error {
+	return c.doHTTPBoth("PUT", c.url("zones/%s", z.Zone.Zone), z)
+}
+
+// UpdateZone takes a *Zone and modifies basic details of a DNS zone
+func (c *Client) UpdateZone(z *Zone) error {
+	return c.doHTTPBoth("POST", c.url("zones/%s", z.Zone.Zone), z)
+}
+
+// CreateRecord takes a *Record and creates a new DNS record in the specified zone, for the specified domain, of the given record type
+func (c *Client) CreateRecord(r *Record) error {
+	return c.doHTTPBoth("PUT", c.url("zones/%s/%s/%s", r.Zone, r.Domain, r.Type), r)
+}
+
+// GetRecord takes a zone, domain and record type t and returns full configuration for a DNS record
+func (c *Client) GetRecord(zone string, domain string, t string) (*Record, error) {
+	r := NewRecord(zone, domain, t)
+	_, err := c.doHTTPUnmarshal("GET", c.url("zones/%s/%s/%s", r.Zone, r.Domain, r.Type), nil, r)
+	return r, err
+}
+
+// DeleteRecord takes a zone, domain and record type t and removes an existing record and all associated answers and configuration details
+func (c *Client) DeleteRecord(zone string, domain string, t string) error {
+	return c.doHTTPDelete(c.url("zones/%s/%s/%s", zone, domain, t))
+}
+
+// UpdateRecord takes a *Record and modifies configuration details for an existing DNS record
+func (c *Client) UpdateRecord(r *Record) error {
+	return c.doHTTPBoth("POST", c.url("zones/%s/%s/%s", r.Zone, r.Domain, r.Type), r)
+}
+
+// CreateDataSource takes a *DataSource and creates a new data source
+func (c *Client) CreateDataSource(ds *nsone.DataSource) error {
+	return c.doHTTPBoth("PUT", c.url("data/sources"), ds)
+}
+
+// GetDataSource takes an ID returns the details for a single data source
+func (c *Client) GetDataSource(id string) (*nsone.DataSource, error) {
+	ds := nsone.DataSource{}
+	_, err := c.doHTTPUnmarshal("GET", c.url("data/sources/%s", id), nil, &ds)
+	return &ds, err
+}
+
+// DeleteDataSource takes an ID and removes an existing data source and all connected feeds from the source
+func (c *Client) DeleteDataSource(id string) error {
+	return c.doHTTPDelete(c.url("data/sources/%s", id))
+}
+
+// UpdateDataSource takes a *DataSource modifies basic details of a data source
+func (c *Client) UpdateDataSource(ds *nsone.DataSource) error {
+	return c.doHTTPBoth("POST", c.url("data/sources/%s", ds.Id), ds)
+}
+
+// CreateDataFeed takes a *DataFeed and connects a new data feed to an existing data source
+func (c *Client) CreateDataFeed(df *nsone.DataFeed) error {
+	return c.doHTTPBoth("PUT", c.url("data/feeds/%s", df.SourceId), df)
+}
+
+// GetDataFeed takes a data source ID and a data feed ID and returns the details of a single data feed
+func (c *Client) GetDataFeed(dsID string, dfID string) (*nsone.DataFeed, error) {
+	df := nsone.NewDataFeed(dsID)
+	_, err := c.doHTTPUnmarshal("GET", c.url("data/feeds/%s/%s", dsID, dfID), nil, df)
+	return df, err
+}
+
+// DeleteDataFeed takes a data source ID and a data feed ID and disconnects the feed from the data source and all attached destination metadata tables
+func (c *Client) DeleteDataFeed(dsID string, dfID string) error {
+	return c.doHTTPDelete(c.url("data/feeds/%s/%s", dsID, dfID))
+}
+
+// UpdateDataFeed takes a *DataFeed and modifies and existing data feed
+func (c *Client) UpdateDataFeed(df *nsone.DataFeed) error {
+	return c.doHTTPBoth("POST", c.url("data/feeds/%s/%s", df.SourceId, df.Id), df)
+}
+
+// GetMonitoringJob takes an ID and returns details for a specific monitoring job
+func (c *Client) GetMonitoringJob(id string) (nsone.MonitoringJob, error) {
+	var mj nsone.MonitoringJob
+	_, err := c.doHTTPUnmarshal("GET", c.url("monitoring/jobs/%s", id), nil, &mj)
+	return mj, err
+}
+
+// CreateMonitoringJob takes a *MonitoringJob and creates a new monitoring job
+func (c *Client) CreateMonitoringJob(mj *nsone.MonitoringJob) error {
+	return c.doHTTPBoth("PUT", c.url("monitoring/jobs"), mj)
+}
+
+// DeleteMonitoringJob takes an ID and immediately terminates and deletes and existing monitoring job
+func (c *Client) DeleteMonitoringJob(id string) error {
+	return c.doHTTPDelete(c.url("monitoring/jobs/%s", id))
+}
+
+// UpdateMonitoringJob takes a *MonitoringJob and change the configuration details of an existing monitoring job
+func (c *Client) UpdateMonitoringJob(mj *nsone.MonitoringJob) error {
+	return c.doHTTPBoth("POST", c.url("monitoring/jobs/%s", mj.Id), mj)
+}
+
+// GetUser takes a username and returns the details for a single user
+func (c *Client) GetUser(username string) (nsone.User, error) {
+	var u nsone.User
+	_, err := c.doHTTPUnmarshal("GET", c.url("account/users/%s", username), nil, &u)
+	return u, err
+}
+
+// CreateUser takes a *User and creates a new user
+func (c *Client) CreateUser(u *nsone.User) error {
+	return c.doHTTPBoth("PUT", c.url("account/users/%s", u.Username), u)
+}
+
+// DeleteUser takes a username and deletes a user from the account
+func (c *Client) DeleteUser(username string) error {
+	return c.doHTTPDelete(c.url("account/users/%s", username))
+}
+
+// UpdateUser takes a *User and change contact details, notification settings or access rights for a user
+func (c *Client) UpdateUser(user *nsone.User) error {
+	return c.doHTTPBoth("POST", c.url("account/users/%s", user.Username), user)
+}
+
+// GetApikey takes an ID and returns details, including permissions, for a single API key
+func (c *Client) GetApikey(id string) (nsone.Apikey, error) {
+	var k nsone.Apikey
+	_, err := c.doHTTPUnmarshal("GET", c.url("account/apikeys/%s", id), nil, &k)
+	return k, err
+}
+
+// CreateApikey takes an *Apikey and creates a new API key
+func (c *Client) CreateApikey(k *nsone.Apikey) error {
+	return c.doHTTPBoth("PUT", c.url("account/apikeys/%s", k.Id), k)
+}
+
+// DeleteApikey takes an ID and deletes and API key
+func (c *Client) DeleteApikey(id string) error {
+	return c.doHTTPDelete(c.url("account/apikeys/%s", id))
+}
+
+// UpdateApikey takes an *Apikey and change name or access rights for an API key
+func (c *Client) UpdateApikey(k *nsone.Apikey) error {
+	return c.doHTTPBoth("POST", c.url("account/apikeys/%s", k.Id), k)
+}
+
+// GetTeam takes an ID and returns details, including permissions, for a single team
+func (c *Client) GetTeam(id string) (nsone.Team, error) {
+	var t nsone.Team
+	_, err := c.doHTTPUnmarshal("GET", c.url("account/teams/%s", id), nil, &t)
+	return t, err
+}
+
+// CreateTeam takes a *Team and creates a new team
+func (c *Client) CreateTeam(t *nsone.Team) error {
+	return c.doHTTPBoth("PUT", c.url("account/teams"), t)
+}
+
+// DeleteTeam takes an ID and deletes a team. Any users of API keys that belong to the team will be removed from the team.
+func (c *Client) DeleteTeam(id string) error {
+	return c.doHTTPDelete(c.url("account/teams/%s", id))
+}
+
+// UpdateTeam takes a *Team and change name or access rights for a team
+func (c *Client) UpdateTeam(t *nsone.Team) error {
+	return c.doHTTPBoth("POST", c.url("account/teams/%s", t.Id), t)
+}
+
+// GetTsigKey takes a name and returns a single TSIG key
+func (c *Client) GetTsigKey(name string) (*TsigKey, error) {
+	k := TsigKey{Name: name}
+	_, err := c.doHTTPUnmarshal("GET", c.url("tsig/%s", name), nil, &k)
+	return &k, err
+}
+
+// CreateTsigKey takes a *TsigKey and creates a new TSIG key
+func (c *Client) CreateTsigKey(k *TsigKey) error {
+	return c.doHTTPBoth("PUT", c.url("tsig/%s", k.Name), k)
+}
+
+// DeleteTsigKey takes a name and deletes a TSIG key
+func (c *Client) DeleteTsigKey(name string) error {
+	return c.doHTTPDelete(c.url("tsig/%s", name))
+}
+
+// UpdateTsigKey takes a *TsigKey and changes the algorithm or secret of a TSIG key
+func (c *Client) UpdateTsigKey(k *TsigKey) error {
+	return c.doHTTPBoth("POST", c.url("tsig/%s", k.Name), k)
+}
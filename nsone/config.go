@@ -0,0 +1,86 @@
+package nsone
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Config holds the provider block settings used to build the API client.
+type Config struct {
+	Key        string
+	Endpoint   string
+	IgnoreSSL  bool
+	CACertFile string
+	HTTPProxy  string
+	Debug      bool
+
+	RateLimitStrategy   string
+	MaxParallelRequests int
+
+	RetryMax     int
+	RetryMaxWait time.Duration
+}
+
+// Client builds the *Client the resources use from the provider settings.
+func (c *Config) Client() (*Client, error) {
+	transport, err := c.transport()
+	if err != nil {
+		return nil, err
+	}
+	if c.Endpoint != "" {
+		if _, err := url.Parse(c.Endpoint); err != nil {
+			return nil, fmt.Errorf("invalid endpoint %q: %s", c.Endpoint, err)
+		}
+	}
+	var rt http.RoundTripper = transport
+	if c.Debug {
+		rt = newLoggingTransport(transport)
+	}
+	n := NewClient(c.Key, c.Endpoint, &http.Client{Transport: rt})
+	if err := n.SetRateLimit(c.RateLimitStrategy, c.MaxParallelRequests); err != nil {
+		return nil, err
+	}
+	n.SetRetry(c.RetryMax, c.RetryMaxWait)
+	return n, nil
+}
+
+func (c *Config) transport() (*http.Transport, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: c.IgnoreSSL,
+	}
+	if c.CACertFile != "" {
+		pem, err := ioutil.ReadFile(c.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading ca_cert_file: %s", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in ca_cert_file %q", c.CACertFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if c.HTTPProxy != "" {
+		proxyURL, err := url.Parse(c.HTTPProxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid http_proxy %q: %s", c.HTTPProxy, err)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	return &http.Transport{
+		Proxy:           proxy,
+		TLSClientConfig: tlsConfig,
+		// Like the ns1-go client, stick to HTTP/1.1.
+		TLSNextProto: make(map[string]func(authority string, c *tls.Conn) http.RoundTripper),
+	}, nil
+}
@@ -0,0 +1,50 @@
+package nsone
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestConfig_endpoint(t *testing.T) {
+	c := Config{Key: "xxx", Endpoint: "https://nsone.example.com/v1"}
+	client, err := c.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if u := client.url("zones/%s", "example.com"); u != "https://nsone.example.com/v1/zones/example.com" {
+		t.Fatalf("Bad url: %s", u)
+	}
+
+	c = Config{Key: "xxx"}
+	client, err = c.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if client.Endpoint != defaultEndpoint {
+		t.Fatalf("Bad default endpoint: %s", client.Endpoint)
+	}
+}
+
+func TestConfig_transport(t *testing.T) {
+	c := Config{IgnoreSSL: true, HTTPProxy: "http://proxy.example.com:3128"}
+	tr, err := c.transport()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !tr.TLSClientConfig.InsecureSkipVerify {
+		t.Fatalf("ignore_ssl not applied")
+	}
+	req, _ := http.NewRequest("GET", "https://api.nsone.net/v1/zones", nil)
+	proxy, err := tr.Proxy(req)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if proxy == nil || proxy.Host != "proxy.example.com:3128" {
+		t.Fatalf("Bad proxy: %v", proxy)
+	}
+
+	c = Config{CACertFile: "/nonexistent/ca.pem"}
+	if _, err := c.transport(); err == nil {
+		t.Fatalf("expected error for missing ca_cert_file")
+	}
+}
@@ -0,0 +1,46 @@
+package nsone
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// dataSourceSchemaFromResource returns a copy of a resource's schema for use
+// by the matching data source: every attribute becomes computed, except for
+// the named ones, which become the data source's required arguments. This
+// keeps the data source's attributes in the same shapes as the resource's,
+// so the resource's ...ToResourceData function can fill in both.
+func dataSourceSchemaFromResource(s map[string]*schema.Schema, required ...string) map[string]*schema.Schema {
+	ds := computedSchemaMap(s)
+	for _, k := range required {
+		ds[k] = &schema.Schema{
+			Type:     s[k].Type,
+			Required: true,
+		}
+	}
+	return ds
+}
+
+func computedSchemaMap(s map[string]*schema.Schema) map[string]*schema.Schema {
+	ds := make(map[string]*schema.Schema, len(s))
+	for k, v := range s {
+		ds[k] = computedSchema(v)
+	}
+	return ds
+}
+
+func computedSchema(s *schema.Schema) *schema.Schema {
+	c := &schema.Schema{
+		Type:        s.Type,
+		Computed:    true,
+		Set:         s.Set,
+		Sensitive:   s.Sensitive,
+		Description: s.Description,
+	}
+	switch e := s.Elem.(type) {
+	case *schema.Resource:
+		c.Elem = &schema.Resource{Schema: computedSchemaMap(e.Schema)}
+	case *schema.Schema:
+		c.Elem = e
+	}
+	return c
+}
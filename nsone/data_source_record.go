@@ -0,0 +1,21 @@
+package nsone
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func recordDataSource() *schema.Resource {
+	return &schema.Resource{
+		Schema: dataSourceSchemaFromResource(recordResource().Schema, "zone", "domain", "type"),
+		Read:   RecordDataSourceRead,
+	}
+}
+
+func RecordDataSourceRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	r, err := client.GetRecord(d.Get("zone").(string), recordDomain(d), d.Get("type").(string))
+	if err != nil {
+		return err
+	}
+	return recordToResourceData(d, r)
+}
@@ -0,0 +1,164 @@
+package nsone
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+
+	"nsone/filterchain"
+)
+
+func recordSimulationDataSource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"zone": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"domain": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"type": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"country": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegionCode,
+			},
+			"us_state": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegionCode,
+			},
+			"ca_province": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegionCode,
+			},
+			"georegion": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(georegions, false),
+			},
+			"latitude": &schema.Schema{
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				ValidateFunc: validation.FloatBetween(-90, 90),
+			},
+			"longitude": &schema.Schema{
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				ValidateFunc: validation.FloatBetween(-180, 180),
+			},
+			"ip": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsIPAddress,
+			},
+			"asn": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"feeds": &schema.Schema{
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"seed": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  0,
+			},
+			"answers": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"answer": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"region": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"skipped_filters": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+		Read: RecordSimulationDataSourceRead,
+	}
+}
+
+func RecordSimulationDataSourceRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	r, err := client.GetRecord(d.Get("zone").(string), recordDomain(d), d.Get("type").(string))
+	if err != nil {
+		return err
+	}
+
+	req := filterchain.Request{
+		Country:    d.Get("country").(string),
+		USState:    d.Get("us_state").(string),
+		CAProvince: d.Get("ca_province").(string),
+		GeoRegion:  d.Get("georegion").(string),
+		ASN:        d.Get("asn").(int),
+	}
+	// 0 is a real latitude and longitude, so GetOk can't be used.
+	lat, latOk := d.GetOkExists("latitude")
+	long, longOk := d.GetOkExists("longitude")
+	if latOk != longOk {
+		return fmt.Errorf("latitude and longitude must be given together")
+	}
+	if latOk {
+		req.HasLocation = true
+		req.Latitude = lat.(float64)
+		req.Longitude = long.(float64)
+	}
+	if v, ok := d.GetOk("ip"); ok {
+		req.IP = net.ParseIP(v.(string))
+	}
+
+	rec := &filterchain.Record{
+		Answers:    r.Answers,
+		Filters:    r.Filters,
+		Meta:       r.Meta,
+		RegionMeta: make(map[string]map[string]interface{}, len(r.Regions)),
+	}
+	for name, region := range r.Regions {
+		rec.RegionMeta[name] = region.Meta
+	}
+	feeds := d.Get("feeds").(map[string]interface{})
+	result := filterchain.Simulate(rec, req, feeds, rand.New(rand.NewSource(int64(d.Get("seed").(int)))))
+
+	answers := make([]map[string]interface{}, len(result.Answers))
+	rdata := make([]string, len(result.Answers))
+	for i, a := range result.Answers {
+		m := answerToMap(r.Type, a)
+		answers[i] = map[string]interface{}{
+			"answer": m["answer"],
+			"region": a.Region,
+		}
+		rdata[i] = m["answer"].(string)
+	}
+	d.SetId(strconv.Itoa(hashcode.String(r.Id + "," + strings.Join(rdata, ","))))
+	if err := d.Set("answers", answers); err != nil {
+		return err
+	}
+	return d.Set("skipped_filters", result.Skipped)
+}
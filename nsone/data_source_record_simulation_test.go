@@ -0,0 +1,45 @@
+package nsone
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccRecordSimulationDataSource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRecordDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccRecordSimulationDataSource_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.nsone_record_simulation.ny", "answers.#", "2"),
+					resource.TestCheckResourceAttr("data.nsone_record_simulation.ny", "answers.0.answer", "test2.terraform.io"),
+					resource.TestCheckResourceAttr("data.nsone_record_simulation.ny", "answers.0.region", "ny"),
+					resource.TestCheckResourceAttr("data.nsone_record_simulation.ny", "skipped_filters.#", "0"),
+					resource.TestCheckResourceAttr("data.nsone_record_simulation.cal", "answers.0.answer", "test1.terraform.io"),
+				),
+			},
+		},
+	})
+}
+
+const testAccRecordSimulationDataSource_basic = testAccRecord_basic + `
+
+data "nsone_record_simulation" "ny" {
+	zone = "${nsone_record.foobar.zone}"
+	domain = "${nsone_record.foobar.domain}"
+	type = "${nsone_record.foobar.type}"
+	country = "US"
+	us_state = "NY"
+}
+
+data "nsone_record_simulation" "cal" {
+	zone = "${nsone_record.foobar.zone}"
+	domain = "${nsone_record.foobar.domain}"
+	type = "${nsone_record.foobar.type}"
+	country = "US"
+	us_state = "CA"
+}`
@@ -0,0 +1,36 @@
+package nsone
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccRecordDataSource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRecordDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccRecordDataSource_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.nsone_record.foobar", "id", "nsone_record.foobar", "id"),
+					resource.TestCheckResourceAttr("data.nsone_record.foobar", "ttl", "60"),
+					resource.TestCheckResourceAttr("data.nsone_record.foobar", "answers.#", "2"),
+					resource.TestCheckResourceAttr("data.nsone_record.foobar", "regions.#", "2"),
+					resource.TestCheckResourceAttr("data.nsone_record.foobar", "filters.#", "2"),
+					resource.TestCheckResourceAttr("data.nsone_record.foobar", "filters.0.filter", "up"),
+				),
+			},
+		},
+	})
+}
+
+const testAccRecordDataSource_basic = testAccRecord_basic + `
+
+data "nsone_record" "foobar" {
+	zone = "${nsone_record.foobar.zone}"
+	domain = "${nsone_record.foobar.domain}"
+	type = "${nsone_record.foobar.type}"
+}`
@@ -0,0 +1,115 @@
+package nsone
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func recordsDataSource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"zone": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"type": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"domain_regex": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"records": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"domain": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ttl": &schema.Schema{
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"link": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"answer_count": &schema.Schema{
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"short_answers": &schema.Schema{
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+		Read: RecordsDataSourceRead,
+	}
+}
+
+func RecordsDataSourceRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	z, err := client.GetZone(d.Get("zone").(string))
+	if err != nil {
+		return err
+	}
+
+	recordType := strings.ToUpper(d.Get("type").(string))
+	var domainRegex *regexp.Regexp
+	if v, ok := d.GetOk("domain_regex"); ok {
+		domainRegex = regexp.MustCompile(v.(string))
+	}
+
+	zrs := z.Records
+	sort.Slice(zrs, func(i, j int) bool {
+		if zrs[i].Domain != zrs[j].Domain {
+			return zrs[i].Domain < zrs[j].Domain
+		}
+		return zrs[i].Type < zrs[j].Type
+	})
+
+	records := make([]map[string]interface{}, 0, len(zrs))
+	ids := make([]string, 0, len(zrs))
+	for _, zr := range zrs {
+		if recordType != "" && zr.Type != recordType {
+			continue
+		}
+		if domainRegex != nil && !domainRegex.MatchString(zr.Domain) {
+			continue
+		}
+		records = append(records, map[string]interface{}{
+			"id":            zr.Id,
+			"domain":        zr.Domain,
+			"type":          zr.Type,
+			"ttl":           zr.Ttl,
+			"link":          zr.Link,
+			"answer_count":  len(zr.ShortAns),
+			"short_answers": zr.ShortAns,
+		})
+		ids = append(ids, zr.Id)
+	}
+	d.SetId(strconv.Itoa(hashcode.String(z.Id + ":" + strings.Join(ids, ","))))
+	return d.Set("records", records)
+}
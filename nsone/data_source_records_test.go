@@ -0,0 +1,42 @@
+package nsone
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccRecordsDataSource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRecordDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccRecordsDataSource_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.nsone_records.cnames", "records.#", "1"),
+					resource.TestCheckResourceAttr("data.nsone_records.cnames", "records.0.domain", "test.terraform.io"),
+					resource.TestCheckResourceAttr("data.nsone_records.cnames", "records.0.type", "CNAME"),
+					resource.TestCheckResourceAttr("data.nsone_records.cnames", "records.0.ttl", "60"),
+					resource.TestCheckResourceAttr("data.nsone_records.cnames", "records.0.answer_count", "2"),
+					resource.TestCheckResourceAttr("data.nsone_records.none", "records.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+const testAccRecordsDataSource_basic = testAccRecord_basic + `
+
+data "nsone_records" "cnames" {
+	zone = "${nsone_zone.test.zone}"
+	type = "CNAME"
+	depends_on = ["nsone_record.foobar"]
+}
+
+data "nsone_records" "none" {
+	zone = "${nsone_zone.test.zone}"
+	domain_regex = "^nothing\\."
+	depends_on = ["nsone_record.foobar"]
+}`
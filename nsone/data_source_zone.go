@@ -0,0 +1,34 @@
+package nsone
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func zoneDataSource() *schema.Resource {
+	s := dataSourceSchemaFromResource(zoneResource().Schema, "zone")
+	// The resource keeps dns_servers as a comma separated string for
+	// backwards compatibility; the data source exposes a proper list.
+	s["dns_servers"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+	return &schema.Resource{
+		Schema: s,
+		Read:   ZoneDataSourceRead,
+	}
+}
+
+func ZoneDataSourceRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	z, err := client.GetZone(d.Get("zone").(string))
+	if err != nil {
+		return err
+	}
+	zoneAttributesToResourceData(d, z)
+	d.Set("dns_servers", z.Dns_servers)
+	if z.Secondary != nil && z.Secondary.Enabled {
+		d.Set("primary", z.Secondary.Primary_ip)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package nsone
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccZoneDataSource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckZoneDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccZoneDataSource_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.nsone_zone.foobar", "id", "nsone_zone.foobar", "id"),
+					resource.TestCheckResourceAttr("data.nsone_zone.foobar", "ttl", "3600"),
+					resource.TestCheckResourceAttr("data.nsone_zone.foobar", "nx_ttl", "3600"),
+					resource.TestCheckResourceAttrSet("data.nsone_zone.foobar", "dns_servers.0"),
+				),
+			},
+		},
+	})
+}
+
+const testAccZoneDataSource_basic = `
+resource "nsone_zone" "foobar" {
+	zone = "terraform.io"
+	ttl = 3600
+	nx_ttl = 3600
+}
+
+data "nsone_zone" "foobar" {
+	zone = "${nsone_zone.foobar.zone}"
+}`
@@ -0,0 +1,138 @@
+package nsone
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	nsone "gopkg.in/sarguru/ns1-go.v18"
+)
+
+func zonesDataSource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name_regex": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"secondary_only": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"linked_only": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"network": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"zones": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"zone": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"link": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"primary": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+		Read: ZonesDataSourceRead,
+	}
+}
+
+// zonesFilter is the set of conditions a zone must meet to be returned by
+// the nsone_zones data source.
+type zonesFilter struct {
+	nameRegex     *regexp.Regexp
+	secondaryOnly bool
+	linkedOnly    bool
+	network       *int
+}
+
+func (f zonesFilter) matches(z nsone.Zone) bool {
+	if f.nameRegex != nil && !f.nameRegex.MatchString(z.Zone) {
+		return false
+	}
+	if f.secondaryOnly && (z.Secondary == nil || !z.Secondary.Enabled) {
+		return false
+	}
+	if f.linkedOnly && z.Link == "" {
+		return false
+	}
+	if f.network != nil {
+		found := false
+		for _, n := range z.Networks {
+			if n == *f.network {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func ZonesDataSourceRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	var f zonesFilter
+	if v, ok := d.GetOk("name_regex"); ok {
+		f.nameRegex = regexp.MustCompile(v.(string))
+	}
+	f.secondaryOnly = d.Get("secondary_only").(bool)
+	f.linkedOnly = d.Get("linked_only").(bool)
+	// network 0 is a real network, so GetOk can't be used.
+	if v, ok := d.GetOkExists("network"); ok {
+		network := v.(int)
+		f.network = &network
+	}
+
+	zl, err := client.GetZones()
+	if err != nil {
+		return err
+	}
+	sort.Slice(zl, func(i, j int) bool { return zl[i].Zone < zl[j].Zone })
+
+	zones := make([]map[string]interface{}, 0, len(zl))
+	names := make([]string, 0, len(zl))
+	for _, z := range zl {
+		if !f.matches(z) {
+			continue
+		}
+		m := map[string]interface{}{
+			"zone": z.Zone,
+			"id":   z.Id,
+			"link": z.Link,
+		}
+		if z.Secondary != nil && z.Secondary.Enabled {
+			m["primary"] = z.Secondary.Primary_ip
+		}
+		zones = append(zones, m)
+		names = append(names, z.Zone)
+	}
+	d.SetId(strconv.Itoa(hashcode.String(strings.Join(names, ","))))
+	return d.Set("zones", zones)
+}
@@ -0,0 +1,49 @@
+package nsone
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccZonesDataSource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckZoneDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccZonesDataSource_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.nsone_zones.all", "zones.#", "2"),
+					resource.TestCheckResourceAttr("data.nsone_zones.all", "zones.0.zone", "a.terraform.io"),
+					resource.TestCheckResourceAttr("data.nsone_zones.all", "zones.1.zone", "b.terraform.io"),
+					resource.TestCheckResourceAttr("data.nsone_zones.linked", "zones.#", "1"),
+					resource.TestCheckResourceAttr("data.nsone_zones.linked", "zones.0.zone", "b.terraform.io"),
+					resource.TestCheckResourceAttr("data.nsone_zones.linked", "zones.0.link", "a.terraform.io"),
+				),
+			},
+		},
+	})
+}
+
+const testAccZonesDataSource_basic = `
+resource "nsone_zone" "a" {
+	zone = "a.terraform.io"
+}
+
+resource "nsone_zone" "b" {
+	zone = "b.terraform.io"
+	link = "${nsone_zone.a.zone}"
+}
+
+data "nsone_zones" "all" {
+	name_regex = "^[ab]\\.terraform\\.io$"
+	depends_on = ["nsone_zone.a", "nsone_zone.b"]
+}
+
+data "nsone_zones" "linked" {
+	name_regex = "\\.terraform\\.io$"
+	linked_only = true
+	depends_on = ["nsone_zone.a", "nsone_zone.b"]
+}`
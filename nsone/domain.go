@@ -0,0 +1,63 @@
+package nsone
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// normalizeDomain returns a domain name in the form the API uses: lower case,
+// without a trailing dot.
+func normalizeDomain(domain string) string {
+	return strings.ToLower(strings.TrimSuffix(domain, "."))
+}
+
+// expandDomain returns the fully qualified name of a record's domain in zone.
+// The domain may be given relative to the zone: "@" is the zone itself, and a
+// single label such as "www" is a name directly under it. Anything else is
+// taken to be fully qualified already.
+func expandDomain(zone, domain string) string {
+	zone = normalizeDomain(zone)
+	switch {
+	case domain == "@":
+		return zone
+	case domain != "" && !strings.Contains(strings.TrimSuffix(domain, "."), "."):
+		if normalizeDomain(domain) != zone {
+			return normalizeDomain(domain) + "." + zone
+		}
+	}
+	return normalizeDomain(domain)
+}
+
+// domainInZone reports whether a fully qualified domain is zone or a name
+// under it.
+func domainInZone(zone, domain string) bool {
+	zone, domain = normalizeDomain(zone), normalizeDomain(domain)
+	return domain == zone || strings.HasSuffix(domain, "."+zone)
+}
+
+// recordDomain returns the fully qualified domain of the record d describes.
+func recordDomain(d *schema.ResourceData) string {
+	return expandDomain(d.Get("zone").(string), d.Get("domain").(string))
+}
+
+// suppressEquivalentDomain suppresses the diff between two spellings of the
+// same domain, such as "www", "www.example.com" and "WWW.example.com." in zone
+// example.com.
+func suppressEquivalentDomain(k, old, new string, d *schema.ResourceData) bool {
+	zone := d.Get("zone").(string)
+	return expandDomain(zone, old) == expandDomain(zone, new)
+}
+
+// validateRecordDomain checks that a record's domain is in its zone.
+func validateRecordDomain(d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("zone") || !d.NewValueKnown("domain") {
+		return nil
+	}
+	zone, domain := d.Get("zone").(string), d.Get("domain").(string)
+	if !domainInZone(zone, expandDomain(zone, domain)) {
+		return fmt.Errorf("domain %q is not in zone %q: it must be the zone, a name under it, or a name relative to it such as \"www\" or \"@\"", domain, zone)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package nsone
+
+import "testing"
+
+func TestExpandDomain(t *testing.T) {
+	cases := []struct {
+		zone, domain, expected string
+	}{
+		{"example.com", "@", "example.com"},
+		{"example.com", "www", "www.example.com"},
+		{"example.com", "WWW.", "www.example.com"},
+		{"example.com", "www.example.com", "www.example.com"},
+		{"Example.com.", "WWW.Example.COM.", "www.example.com"},
+		{"example.com", "a.b.example.com", "a.b.example.com"},
+		{"example.com", "www.example.org", "www.example.org"},
+		{"localhost", "localhost", "localhost"},
+	}
+	for _, c := range cases {
+		if got := expandDomain(c.zone, c.domain); got != c.expected {
+			t.Errorf("expandDomain(%q, %q): expected %q, got %q", c.zone, c.domain, c.expected, got)
+		}
+	}
+}
+
+func TestDomainInZone(t *testing.T) {
+	cases := []struct {
+		zone, domain string
+		expected     bool
+	}{
+		{"example.com", "example.com", true},
+		{"example.com", "www.example.com.", true},
+		{"example.com", "WWW.EXAMPLE.COM", true},
+		{"example.com", "www.example.org", false},
+		{"example.com", "badexample.com", false},
+		{"example.com", "com", false},
+	}
+	for _, c := range cases {
+		if got := domainInZone(c.zone, c.domain); got != c.expected {
+			t.Errorf("domainInZone(%q, %q): expected %t, got %t", c.zone, c.domain, c.expected, got)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package nsone
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// APIError is returned by the Client for any response other than a 200.
+// Body is redacted as in the debug log, since Terraform prints the error.
+type APIError struct {
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Status, e.Body)
+}
+
+// NotFoundError is returned by the Client when the API responds with a 404,
+// i.e. the object asked for does not exist (any more).
+type NotFoundError struct {
+	*APIError
+}
+
+func newAPIError(statusCode int, status string, path string, body []byte) error {
+	err := &APIError{
+		StatusCode: statusCode,
+		Status:     status,
+	}
+	if len(body) > 0 {
+		err.Body = redactBody(path, body)
+	}
+	if statusCode == 404 {
+		return &NotFoundError{err}
+	}
+	return err
+}
+
+// isNotFound reports whether err is, or wraps, a *NotFoundError.
+func isNotFound(err error) bool {
+	var nf *NotFoundError
+	return errors.As(err, &nf)
+}
+
+// readError is used by Read functions to handle an error fetching their
+// object. If the object was deleted outside of Terraform it is removed from
+// state, so that the next plan proposes to create it again, rather than
+// failing; any other error is returned.
+func readError(d *schema.ResourceData, kind string, err error) error {
+	if isNotFound(err) {
+		log.Printf("[WARN] NSONE %s %s not found, removing from state", kind, d.Id())
+		d.SetId("")
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,82 @@
+package nsone
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestReadRemovesDeletedObjects(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"not found"}`))
+	}))
+	defer ts.Close()
+	client := NewClient("xxx", ts.URL, nil)
+
+	if _, err := client.GetZone("terraform.io"); !isNotFound(err) {
+		t.Fatalf("expected a not found error, got %#v", err)
+	}
+
+	cases := map[string]struct {
+		resource *schema.Resource
+		raw      map[string]interface{}
+	}{
+		"zone":          {zoneResource(), map[string]interface{}{"zone": "terraform.io"}},
+		"record":        {recordResource(), map[string]interface{}{"zone": "terraform.io", "domain": "test.terraform.io", "type": "A"}},
+		"datasource":    {dataSourceResource(), map[string]interface{}{"name": "test", "sourcetype": "nsone_v1"}},
+		"datafeed":      {dataFeedResource(), map[string]interface{}{"name": "test", "source_id": "abc"}},
+		"monitoringjob": {monitoringJobResource(), map[string]interface{}{"name": "test"}},
+		"user":          {userResource(), map[string]interface{}{"username": "test"}},
+		"apikey":        {apikeyResource(), map[string]interface{}{"name": "test"}},
+		"team":          {teamResource(), map[string]interface{}{"name": "test"}},
+	}
+	for name, c := range cases {
+		d := schema.TestResourceDataRaw(t, c.resource.Schema, c.raw)
+		d.SetId("abc")
+		if err := c.resource.Read(d, client); err != nil {
+			t.Fatalf("%s: err: %s", name, err)
+		}
+		if d.Id() != "" {
+			t.Fatalf("%s: should have been removed from state", name)
+		}
+	}
+}
+
+func TestReadReturnsOtherErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"forbidden"}`))
+	}))
+	defer ts.Close()
+	client := NewClient("xxx", ts.URL, nil)
+
+	d := schema.TestResourceDataRaw(t, zoneResource().Schema, map[string]interface{}{"zone": "terraform.io"})
+	d.SetId("abc")
+	if err := ZoneRead(d, client); err == nil {
+		t.Fatalf("expected error")
+	}
+	if d.Id() != "abc" {
+		t.Fatalf("should not have been removed from state")
+	}
+}
+
+func TestAPIErrorRedactsBody(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message":"invalid key","key":"s3cr3t"}`))
+	}))
+	defer ts.Close()
+	client := NewClient("xxx", ts.URL, nil)
+
+	_, err := client.GetApikey("abc")
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if strings.Contains(err.Error(), "s3cr3t") || !strings.Contains(err.Error(), "invalid key") {
+		t.Fatalf("Bad redaction: %s", err)
+	}
+}
@@ -0,0 +1,271 @@
+package nsone
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// filterConfigKey describes one key of a filter's config: the type its
+// value is sent to the API as, the type of the elements of a list, and, for
+// strings, the values allowed.
+type filterConfigKey struct {
+	typ     schema.ValueType
+	elem    schema.ValueType
+	allowed []string
+}
+
+// filterTypes is the catalog of NSONE filter types, with the config keys
+// each one takes. The config of a filter is given in a typed config block,
+// see filterConfigSchema. The pulsar filters' config is not described, so
+// it can only be given as config_json, which is sent unchecked.
+var filterTypes = map[string]map[string]filterConfigKey{
+	"up":                  {},
+	"geotarget_country":   {},
+	"geotarget_regional":  {},
+	"geotarget_latlong":   {},
+	"geofence_country":    {"remove_no_location": {typ: schema.TypeBool}},
+	"geofence_regional":   {"remove_no_georegion": {typ: schema.TypeBool}},
+	"netfence_asn":        {"remove_no_asn": {typ: schema.TypeBool}},
+	"netfence_prefix":     {"remove_no_ip_prefixes": {typ: schema.TypeBool}},
+	"weighted_shuffle":    {},
+	"weighted_sticky":     {"sticky_by_network": {typ: schema.TypeBool}},
+	"sticky":              {"sticky_by_network": {typ: schema.TypeBool}},
+	"sticky_region":       {"sticky_by_network": {typ: schema.TypeBool}},
+	"shuffle":             {},
+	"select_first_n":      {"N": {typ: schema.TypeInt}},
+	"select_first_region": {},
+	"priority":            {"eliminate": {typ: schema.TypeBool}},
+	"shed_load":           {"metric": {typ: schema.TypeString, allowed: []string{"connections", "requests", "loadavg"}}},
+	"cost":                {},
+	"ipv4_prefix_shuffle": {"N": {typ: schema.TypeInt}},
+	"ipv6_prefix_shuffle": {"N": {typ: schema.TypeInt}},
+
+	"pulsar_availability_threshold": nil,
+	"pulsar_sort":                   nil,
+	"pulsar_stabilize":              nil,
+}
+
+// filterNames returns the names of all the filter types, sorted.
+func filterNames() []string {
+	names := make([]string, 0, len(filterTypes))
+	for name := range filterTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var validateFilterName = validation.StringInSlice(filterNames(), false)
+
+// filterConfigAttr returns the name of the config block attribute for a
+// config key. Attribute names are in lower case, e.g. n for N.
+func filterConfigAttr(key string) string {
+	return strings.ToLower(key)
+}
+
+// filterConfigSchema is the schema of a filter's typed config block, with an
+// attribute for each config key in the catalog. The type and values of each
+// are checked by the schema; which keys a filter takes is checked by
+// filterConfigToAPI. A key is taken by the same type by every filter that
+// takes it.
+func filterConfigSchema() *schema.Schema {
+	s := make(map[string]*schema.Schema)
+	for _, keys := range filterTypes {
+		for k, key := range keys {
+			var validate schema.SchemaValidateFunc
+			if len(key.allowed) > 0 {
+				validate = validation.StringInSlice(key.allowed, false)
+			}
+			ks := &schema.Schema{
+				Type:     key.typ,
+				Optional: true,
+			}
+			if key.typ == schema.TypeList {
+				ks.Elem = &schema.Schema{
+					Type:         key.elem,
+					ValidateFunc: validate,
+				}
+			} else {
+				ks.ValidateFunc = validate
+			}
+			s[filterConfigAttr(k)] = ks
+		}
+	}
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: s,
+		},
+	}
+}
+
+// filterConfig returns the config to send to the API for an element of a
+// record's filters.
+func filterConfig(fi map[string]interface{}) (map[string]interface{}, error) {
+	var block map[string]interface{}
+	if l, _ := fi["config"].([]interface{}); len(l) > 0 && l[0] != nil {
+		block = l[0].(map[string]interface{})
+	}
+	configJSON, _ := fi["config_json"].(string)
+	name, _ := fi["filter"].(string)
+	return filterConfigToAPI(name, block, configJSON)
+}
+
+// filterConfigToAPI turns the config block of a filter into the config the
+// API takes, returning an error for keys the filter does not take. Values
+// left unset, or at their zero value, are not sent. configJSON, if set, is
+// sent as given instead.
+func filterConfigToAPI(filter string, block map[string]interface{}, configJSON string) (map[string]interface{}, error) {
+	keys, ok := filterTypes[filter]
+	if !ok {
+		return nil, fmt.Errorf("unknown filter %q", filter)
+	}
+	config := make(map[string]interface{})
+	for attr, v := range block {
+		if isZeroFilterValue(v) {
+			continue
+		}
+		k, ok := filterConfigKeyName(keys, attr)
+		if !ok {
+			return nil, fmt.Errorf("filter %s does not take config key %s%s", filter, attr, filterConfigKeysHint(keys))
+		}
+		config[k] = v
+	}
+	if configJSON == "" {
+		return config, nil
+	}
+	if len(config) > 0 {
+		return nil, fmt.Errorf("only one of config and config_json can be set")
+	}
+	if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+		return nil, fmt.Errorf("config_json must be a JSON object: %s", err)
+	}
+	return config, nil
+}
+
+// filterConfigFromAPI turns the config of a filter as returned by the API
+// into a config block. The config is read into config_json instead if it
+// was given that way, or if it has keys or values the catalog does not
+// describe for the filter, so none of it is lost.
+func filterConfigFromAPI(filter string, config map[string]interface{}, asJSON bool) (map[string]interface{}, string) {
+	if !asJSON && len(config) == 0 {
+		return nil, ""
+	}
+	block := make(map[string]interface{})
+	for k, v := range config {
+		key, ok := filterTypes[filter][k]
+		if ok {
+			v, ok = filterConfigValue(key, v)
+		}
+		if !ok || asJSON {
+			b, _ := json.Marshal(config)
+			return nil, string(b)
+		}
+		if !isZeroFilterValue(v) {
+			block[filterConfigAttr(k)] = v
+		}
+	}
+	if asJSON {
+		return nil, "{}"
+	}
+	if len(block) == 0 {
+		return nil, ""
+	}
+	return block, ""
+}
+
+// filterConfigValue converts a config value as returned by the API to the
+// type of key, reporting whether it is of that type.
+func filterConfigValue(key filterConfigKey, v interface{}) (interface{}, bool) {
+	switch key.typ {
+	case schema.TypeBool:
+		b, ok := v.(bool)
+		return b, ok
+	case schema.TypeInt:
+		switch n := v.(type) {
+		case int:
+			return n, true
+		case float64:
+			return int(n), n == math.Trunc(n)
+		}
+		return nil, false
+	case schema.TypeString:
+		s, ok := v.(string)
+		if ok && len(key.allowed) > 0 && !stringInSlice(s, key.allowed) {
+			return nil, false
+		}
+		return s, ok
+	case schema.TypeList:
+		l, ok := v.([]interface{})
+		if !ok {
+			return nil, false
+		}
+		elems := make([]interface{}, len(l))
+		for i, e := range l {
+			if elems[i], ok = filterConfigValue(filterConfigKey{typ: key.elem, allowed: key.allowed}, e); !ok {
+				return nil, false
+			}
+		}
+		return elems, true
+	}
+	return nil, false
+}
+
+// isZeroFilterValue reports whether v is unset or the zero value of its
+// type, which is the same as not sending it.
+func isZeroFilterValue(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !t
+	case int:
+		return t == 0
+	case string:
+		return t == ""
+	case []interface{}:
+		return len(t) == 0
+	}
+	return false
+}
+
+func filterConfigKeyName(keys map[string]filterConfigKey, attr string) (string, bool) {
+	for k := range keys {
+		if filterConfigAttr(k) == attr {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+func filterConfigKeysHint(keys map[string]filterConfigKey) string {
+	if keys == nil {
+		return " (its config is not described, give it as config_json)"
+	}
+	if len(keys) == 0 {
+		return " (it takes no config)"
+	}
+	names := make([]string, 0, len(keys))
+	for k := range keys {
+		names = append(names, filterConfigAttr(k))
+	}
+	sort.Strings(names)
+	return fmt.Sprintf(" (it takes %s)", strings.Join(names, ", "))
+}
+
+func stringInSlice(s string, l []string) bool {
+	for _, e := range l {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package nsone
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestFilterConfigToAPI(t *testing.T) {
+	for _, c := range []struct {
+		filter     string
+		block      map[string]interface{}
+		configJSON string
+		expected   map[string]interface{}
+	}{
+		{"select_first_n", map[string]interface{}{"n": 2}, "", map[string]interface{}{"N": 2}},
+		{"geofence_country", map[string]interface{}{"remove_no_location": true}, "", map[string]interface{}{"remove_no_location": true}},
+		{"shed_load", map[string]interface{}{"metric": "loadavg"}, "", map[string]interface{}{"metric": "loadavg"}},
+		// The block holds every key in the catalog; those left at their
+		// zero value are not sent.
+		{"select_first_n", map[string]interface{}{"n": 1, "eliminate": false, "metric": ""}, "", map[string]interface{}{"N": 1}},
+		{"up", nil, "", map[string]interface{}{}},
+		{"pulsar_sort", nil, `{"jobs": ["abc", "def"], "enabled": true}`, map[string]interface{}{"jobs": []interface{}{"abc", "def"}, "enabled": true}},
+	} {
+		config, err := filterConfigToAPI(c.filter, c.block, c.configJSON)
+		if err != nil {
+			t.Fatalf("%s: err: %s", c.filter, err)
+		}
+		if !reflect.DeepEqual(config, c.expected) {
+			t.Fatalf("%s: expected %#v, got %#v", c.filter, c.expected, config)
+		}
+	}
+
+	bad := []struct {
+		filter     string
+		block      map[string]interface{}
+		configJSON string
+	}{
+		{"geotarget_contry", nil, ""},
+		{"select_first_n", map[string]interface{}{"remove_no_location": true}, ""},
+		{"up", map[string]interface{}{"sticky_by_network": true}, ""},
+		{"pulsar_sort", map[string]interface{}{"n": 2}, ""},
+		{"select_first_n", map[string]interface{}{"n": 2}, `{"N": 2}`},
+		{"pulsar_sort", nil, `["abc"]`},
+	}
+	for _, c := range bad {
+		if _, err := filterConfigToAPI(c.filter, c.block, c.configJSON); err == nil {
+			t.Fatalf("expected an error for %s %v %s", c.filter, c.block, c.configJSON)
+		}
+	}
+}
+
+func TestFilterConfigReadBack(t *testing.T) {
+	for filter, config := range map[string]map[string]interface{}{
+		"select_first_n":   {"N": float64(3)},
+		"geofence_country": {"remove_no_location": true},
+		"shed_load":        {"metric": "requests"},
+	} {
+		block, configJSON := filterConfigFromAPI(filter, config, false)
+		if configJSON != "" {
+			t.Fatalf("%s: expected a config block, got config_json %s", filter, configJSON)
+		}
+		sent, err := filterConfigToAPI(filter, block, "")
+		if err != nil {
+			t.Fatalf("%s: err: %s", filter, err)
+		}
+		for k, v := range sent {
+			if f, ok := config[k].(float64); ok {
+				if v != int(f) {
+					t.Fatalf("%s: expected %v, got %v", filter, config[k], v)
+				}
+			} else if v != config[k] {
+				t.Fatalf("%s: expected %v, got %v", filter, config[k], v)
+			}
+		}
+	}
+
+	// Config the catalog does not describe is read into config_json, as
+	// is config given that way.
+	for _, c := range []struct {
+		filter string
+		config map[string]interface{}
+		asJSON bool
+	}{
+		{"pulsar_sort", map[string]interface{}{"jobs": []interface{}{"abc"}}, false},
+		{"select_first_n", map[string]interface{}{"N": float64(2), "foo": "bar"}, false},
+		{"select_first_n", map[string]interface{}{"N": "2"}, false},
+		{"select_first_n", map[string]interface{}{"N": float64(2)}, true},
+	} {
+		block, configJSON := filterConfigFromAPI(c.filter, c.config, c.asJSON)
+		if block != nil || configJSON == "" {
+			t.Fatalf("%s %v: expected config_json, got %#v", c.filter, c.config, block)
+		}
+		sent, err := filterConfigToAPI(c.filter, nil, configJSON)
+		if err != nil {
+			t.Fatalf("%s: err: %s", c.filter, err)
+		}
+		if !reflect.DeepEqual(sent, c.config) {
+			t.Fatalf("%s: expected %#v, got %#v", c.filter, c.config, sent)
+		}
+	}
+}
+
+func TestFilterConfigList(t *testing.T) {
+	filterTypes["test_list"] = map[string]filterConfigKey{
+		"IDs": {typ: schema.TypeList, elem: schema.TypeInt},
+	}
+	defer delete(filterTypes, "test_list")
+
+	s := filterConfigSchema().Elem.(*schema.Resource).Schema["ids"]
+	if s == nil || s.Type != schema.TypeList || s.Elem.(*schema.Schema).Type != schema.TypeInt {
+		t.Fatalf("expected a list of ints, got %#v", s)
+	}
+	config, err := filterConfigToAPI("test_list", map[string]interface{}{"ids": []interface{}{1, 2}}, "")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(config, map[string]interface{}{"IDs": []interface{}{1, 2}}) {
+		t.Fatalf("bad config: %#v", config)
+	}
+	block, _ := filterConfigFromAPI("test_list", map[string]interface{}{"IDs": []interface{}{float64(1), float64(2)}}, false)
+	if !reflect.DeepEqual(block, map[string]interface{}{"ids": []interface{}{1, 2}}) {
+		t.Fatalf("bad block: %#v", block)
+	}
+}
+
+// TestFilterConfigKeys checks that a config key takes the same type in
+// every filter that takes it, since the config block has one attribute for
+// it, and that the attribute's name is a valid one.
+func TestFilterConfigKeys(t *testing.T) {
+	valid := regexp.MustCompile(`^[a-z0-9_]+$`)
+	seen := make(map[string]filterConfigKey)
+	for filter, keys := range filterTypes {
+		for k, key := range keys {
+			attr := filterConfigAttr(k)
+			if !valid.MatchString(attr) {
+				t.Fatalf("%s: bad attribute name %q", filter, attr)
+			}
+			if other, ok := seen[attr]; ok && !reflect.DeepEqual(other, key) {
+				t.Fatalf("%s: config key %s is described differently by another filter", filter, k)
+			}
+			seen[attr] = key
+		}
+	}
+}
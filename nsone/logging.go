@@ -0,0 +1,146 @@
+package nsone
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const redacted = "[REDACTED]"
+
+// redactedHeaders are never logged with their real value.
+var redactedHeaders = []string{"X-NSONE-Key"}
+
+// redactedFields are JSON object keys whose values are never logged, keyed
+// by the API path they appear under: API key secrets (returned when an
+// nsone_apikey is created), TSIG key secrets, both of nsone_tsig_key and in
+// a secondary zone's config, and user emails. Elsewhere "key" is harmless,
+// e.g. in monitoring job rules.
+var redactedFields = map[string][]string{
+	"account/apikeys": []string{"key", "email"},
+	"tsig":            []string{"secret"},
+	"zones":           []string{"key"},
+	"":                []string{"email"},
+}
+
+var redactedFieldRegexps = map[string]*regexp.Regexp{}
+
+func init() {
+	for _, fields := range redactedFields {
+		for _, f := range fields {
+			redactedFieldRegexps[f] = regexp.MustCompile(`("` + f + `"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+		}
+	}
+}
+
+// loggingTransport wraps an http.RoundTripper and logs one line per request
+// and response, with API keys, key secrets and emails redacted, so that
+// TF_LOG output is safe to attach to tickets.
+type loggingTransport struct {
+	transport http.RoundTripper
+}
+
+func newLoggingTransport(t http.RoundTripper) http.RoundTripper {
+	return &loggingTransport{transport: t}
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		reqBody, _ = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+	log.Printf("[DEBUG] nsone: request method=%s url=%s headers=%s body=%s",
+		req.Method, req.URL, redactHeaders(req.Header), redactBody(req.URL.Path, reqBody))
+
+	start := time.Now()
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		log.Printf("[DEBUG] nsone: response method=%s url=%s duration=%s error=%q",
+			req.Method, req.URL, time.Since(start), err)
+		return resp, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+	if err != nil {
+		return resp, err
+	}
+	log.Printf("[DEBUG] nsone: response method=%s url=%s status=%d duration=%s headers=%s body=%s",
+		req.Method, req.URL, resp.StatusCode, time.Since(start), redactHeaders(resp.Header), redactBody(req.URL.Path, respBody))
+	return resp, nil
+}
+
+func redactHeaders(h http.Header) string {
+	c := make(http.Header, len(h))
+	for k, v := range h {
+		c[k] = v
+	}
+	for _, k := range redactedHeaders {
+		if c.Get(k) != "" {
+			c.Set(k, redacted)
+		}
+	}
+	b, _ := json.Marshal(c)
+	return string(b)
+}
+
+// redactBody returns body as a string with the values of the fields
+// redacted for path replaced, at any depth. Bodies that are not JSON are
+// redacted by pattern.
+func redactBody(path string, body []byte) string {
+	if len(body) == 0 {
+		return `""`
+	}
+	fields := redactedFieldsFor(path)
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		s := string(body)
+		for f := range fields {
+			s = redactedFieldRegexps[f].ReplaceAllString(s, `$1"`+redacted+`"`)
+		}
+		return s
+	}
+	b, err := json.Marshal(redactValue(v, fields))
+	if err != nil {
+		return redacted
+	}
+	return string(b)
+}
+
+func redactedFieldsFor(path string) map[string]bool {
+	fields := make(map[string]bool)
+	for prefix, fs := range redactedFields {
+		if strings.Contains(path, prefix) {
+			for _, f := range fs {
+				fields[f] = true
+			}
+		}
+	}
+	return fields
+}
+
+func redactValue(v interface{}, fields map[string]bool) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, fv := range t {
+			if _, ok := fv.(string); ok && fields[strings.ToLower(k)] {
+				t[k] = redacted
+				continue
+			}
+			t[k] = redactValue(fv, fields)
+		}
+	case []interface{}:
+		for i, e := range t {
+			t[i] = redactValue(e, fields)
+		}
+	}
+	return v
+}
@@ -0,0 +1,71 @@
+package nsone
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+	body := redactBody("/v1/account/apikeys", []byte(`{"id":"abc","name":"ci","key":"s3cr3t","teams":[]}`))
+	if strings.Contains(body, "s3cr3t") || !strings.Contains(body, `"name":"ci"`) {
+		t.Fatalf("Bad redaction: %s", body)
+	}
+
+	body = redactBody("/v1/account/users", []byte(`[{"username":"bob","email":"bob@example.com"}]`))
+	if strings.Contains(body, "bob@example.com") || !strings.Contains(body, `"username":"bob"`) {
+		t.Fatalf("Bad redaction: %s", body)
+	}
+
+	body = redactBody("/v1/monitoring/jobs/123", []byte(`{"rules":[{"key":"rtt","value":100}]}`))
+	if !strings.Contains(body, `"key":"rtt"`) {
+		t.Fatalf("monitoring rule key should not be redacted: %s", body)
+	}
+
+	body = redactBody("/v1/tsig/transfer-key", []byte(`{"name":"transfer-key","algorithm":"hmac-sha256","secret":"s3cr3t"}`))
+	if strings.Contains(body, "s3cr3t") || !strings.Contains(body, `"algorithm":"hmac-sha256"`) {
+		t.Fatalf("Bad redaction: %s", body)
+	}
+
+	body = redactBody("/v1/zones/terraform.io", []byte(`{"secondary":{"tsig":{"name":"transfer-key","key":"s3cr3t"}}}`))
+	if strings.Contains(body, "s3cr3t") {
+		t.Fatalf("Bad redaction: %s", body)
+	}
+
+	body = redactBody("/v1/account/apikeys", []byte(`not json "key": "s3cr3t"`))
+	if strings.Contains(body, "s3cr3t") {
+		t.Fatalf("Bad redaction of non-JSON body: %s", body)
+	}
+}
+
+func TestLoggingTransport(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"abc","key":"s3cr3t"}`))
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	client := NewClient("my-api-key", ts.URL+"/v1", &http.Client{Transport: newLoggingTransport(http.DefaultTransport)})
+	k, err := client.GetApikey("abc")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if k.Key != "s3cr3t" {
+		t.Fatalf("response body was not passed through: %+v", k)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "my-api-key") || strings.Contains(out, "s3cr3t") {
+		t.Fatalf("secrets leaked into log: %s", out)
+	}
+	if !strings.Contains(out, "nsone: request method=GET") || !strings.Contains(out, "status=200") {
+		t.Fatalf("missing request/response lines: %s", out)
+	}
+}
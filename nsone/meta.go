@@ -1,12 +1,315 @@
 package nsone
 
 import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 )
 
-func metaSchema() *schema.Schema {
+// georegions are the values allowed for georegion metadata.
+var georegions = []string{"US-WEST", "US-EAST", "US-CENTRAL", "EUROPE", "AFRICA", "ASIAPAC", "SOUTH-AMERICA"}
+
+var validateRegionCode = validation.StringMatch(regexp.MustCompile(`^[A-Z]{2}$`), "must be a two letter code in upper case")
+
+// metaField is one of the NSONE metadata keys. List fields hold values of
+// elem type; validate applies to the value, or to each element of a list.
+type metaField struct {
+	name     string
+	typ      schema.ValueType
+	elem     schema.ValueType
+	validate schema.SchemaValidateFunc
+}
+
+// isNumber reports whether f holds a single number. These are held as
+// strings in the schema, since the SDK cannot tell an unset number from a
+// zero, and zero is a value like any other, e.g. a latitude of 0 is on the
+// equator.
+func (f metaField) isNumber() bool {
+	return f.typ == schema.TypeInt || f.typ == schema.TypeFloat
+}
+
+// metaFields are the metadata keys that can be set on answers, regions and
+// records. Each can instead be pointed at a data feed by setting
+// <name>_feed.
+var metaFields = []metaField{
+	{name: "up", typ: schema.TypeBool},
+	{name: "connections", typ: schema.TypeInt, validate: validation.IntAtLeast(0)},
+	{name: "requests", typ: schema.TypeInt, validate: validation.IntAtLeast(0)},
+	{name: "loadavg", typ: schema.TypeFloat, validate: validation.FloatAtLeast(0)},
+	{name: "pulsar", typ: schema.TypeString},
+	{name: "latitude", typ: schema.TypeFloat, validate: validation.FloatBetween(-90, 90)},
+	{name: "longitude", typ: schema.TypeFloat, validate: validation.FloatBetween(-180, 180)},
+	{name: "georegion", typ: schema.TypeList, elem: schema.TypeString, validate: validation.StringInSlice(georegions, false)},
+	{name: "country", typ: schema.TypeList, elem: schema.TypeString, validate: validateRegionCode},
+	{name: "us_state", typ: schema.TypeList, elem: schema.TypeString, validate: validateRegionCode},
+	{name: "ca_province", typ: schema.TypeList, elem: schema.TypeString, validate: validateRegionCode},
+	{name: "note", typ: schema.TypeString, validate: validation.StringLenBetween(0, 256)},
+	{name: "ip_prefixes", typ: schema.TypeList, elem: schema.TypeString, validate: validation.IsCIDR},
+	{name: "asn", typ: schema.TypeList, elem: schema.TypeInt, validate: validation.IntAtLeast(0)},
+	{name: "priority", typ: schema.TypeInt, validate: validation.IntAtLeast(0)},
+	{name: "weight", typ: schema.TypeFloat, validate: validation.FloatAtLeast(0)},
+	{name: "low_watermark", typ: schema.TypeInt, validate: validation.IntAtLeast(0)},
+	{name: "high_watermark", typ: schema.TypeInt, validate: validation.IntAtLeast(0)},
+}
+
+// metaBlockSchema is the schema of a typed metadata block. Unset values
+// are not sent, nor are empty strings and lists; up defaults to true, and
+// an up of false marks the answer down. Numbers are held as strings, ""
+// being unset.
+func metaBlockSchema() *schema.Schema {
+	s := make(map[string]*schema.Schema, 2*len(metaFields))
+	for _, f := range metaFields {
+		fs := &schema.Schema{
+			Type:     f.typ,
+			Optional: true,
+		}
+		switch {
+		case f.typ == schema.TypeList:
+			fs.Elem = &schema.Schema{
+				Type:         f.elem,
+				ValidateFunc: f.validate,
+			}
+		case f.isNumber():
+			fs.Type = schema.TypeString
+			fs.ValidateFunc = validateMetaNumber(f)
+			fs.DiffSuppressFunc = suppressEquivalentMetaNumber(f)
+		default:
+			fs.ValidateFunc = f.validate
+		}
+		if f.name == "up" {
+			fs.Default = true
+		}
+		s[f.name] = fs
+		s[f.name+"_feed"] = &schema.Schema{
+			Type:     schema.TypeString,
+			Optional: true,
+		}
+	}
 	return &schema.Schema{
-		Type:     schema.TypeMap,
+		Type:     schema.TypeList,
 		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: s,
+		},
+	}
+}
+
+// metaBlock returns the map held by a metadata block attribute, or nil if
+// the block is not set.
+func metaBlock(v interface{}) map[string]interface{} {
+	l, ok := v.([]interface{})
+	if !ok || len(l) == 0 || l[0] == nil {
+		return nil
+	}
+	return l[0].(map[string]interface{})
+}
+
+// metaBlockToAPI turns a metadata block into the metadata the API takes.
+// A feed takes the place of a static value for the same key.
+func metaBlockToAPI(block map[string]interface{}) (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+	for _, f := range metaFields {
+		if feed, _ := block[f.name+"_feed"].(string); feed != "" {
+			if f.name != "up" && !isZeroMeta(block[f.name]) {
+				return nil, fmt.Errorf("only one of %s and %s_feed can be set", f.name, f.name)
+			}
+			m[f.name] = map[string]interface{}{"feed": feed}
+			continue
+		}
+		v, ok := block[f.name]
+		if !ok || isZeroMeta(v) {
+			continue
+		}
+		if f.name == "up" && v.(bool) {
+			// up defaults to true, which is also what the API assumes
+			// when it is not set.
+			continue
+		}
+		if f.isNumber() {
+			n, ok := metaValue(f.typ, v)
+			if !ok {
+				return nil, fmt.Errorf("%s must be a number, got %q", f.name, v)
+			}
+			v = n
+		}
+		m[f.name] = v
+	}
+	if up, ok := block["up"].(bool); ok && !up {
+		if _, fed := m["up"]; !fed {
+			m["up"] = false
+		}
+	}
+	return m, nil
+}
+
+// validateMetaNumber checks that the string held for number field f parses
+// as its type, and the number with f's own validation.
+func validateMetaNumber(f metaField) schema.SchemaValidateFunc {
+	return func(i interface{}, k string) ([]string, []error) {
+		v, _ := i.(string)
+		n, ok := metaValue(f.typ, v)
+		if !ok {
+			return nil, []error{fmt.Errorf("expected %s to be a number, got %q", k, v)}
+		}
+		if f.validate == nil {
+			return nil, nil
+		}
+		return f.validate(n, k)
+	}
+}
+
+// suppressEquivalentMetaNumber suppresses the diff between two spellings of
+// the same number, such as 1 and 1.0.
+func suppressEquivalentMetaNumber(f metaField) schema.SchemaDiffSuppressFunc {
+	return func(k, old, new string, d *schema.ResourceData) bool {
+		o, ok := metaValue(f.typ, old)
+		if !ok {
+			return false
+		}
+		n, ok := metaValue(f.typ, new)
+		return ok && o == n
+	}
+}
+
+func isZeroMeta(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !t
+	case int:
+		return t == 0
+	case float64:
+		return t == 0
+	case string:
+		return t == ""
+	case []interface{}:
+		return len(t) == 0
+	}
+	return false
+}
+
+// metaAPIToBlock turns metadata returned by the API into a metadata block,
+// converting each value to the type of its field. Keys this provider does
+// not know about are dropped.
+func metaAPIToBlock(meta map[string]interface{}) map[string]interface{} {
+	block := map[string]interface{}{"up": true}
+	for _, f := range metaFields {
+		v, ok := meta[f.name]
+		if !ok || v == nil {
+			continue
+		}
+		if feed, ok := v.(map[string]interface{}); ok {
+			if id, ok := feed["feed"].(string); ok {
+				block[f.name+"_feed"] = id
+			}
+			continue
+		}
+		if f.typ == schema.TypeList {
+			var raw []interface{}
+			switch t := v.(type) {
+			case []interface{}:
+				raw = t
+			case string:
+				for _, s := range strings.Split(t, ",") {
+					raw = append(raw, strings.TrimSpace(s))
+				}
+			default:
+				raw = []interface{}{t}
+			}
+			l := make([]interface{}, 0, len(raw))
+			for _, e := range raw {
+				if c, ok := metaValue(f.elem, e); ok {
+					l = append(l, c)
+				}
+			}
+			block[f.name] = l
+			continue
+		}
+		if c, ok := metaValue(f.typ, v); ok {
+			if f.isNumber() {
+				c = metaNumberString(c)
+			}
+			block[f.name] = c
+		}
+	}
+	return block
+}
+
+// metaNumberString returns a number as it is held in a metadata block.
+func metaNumberString(n interface{}) string {
+	switch t := n.(type) {
+	case int:
+		return strconv.Itoa(t)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	}
+	return ""
+}
+
+// metaValue converts a single metadata value as decoded from the API's JSON
+// to type t.
+func metaValue(t schema.ValueType, v interface{}) (interface{}, bool) {
+	switch t {
+	case schema.TypeBool:
+		switch b := v.(type) {
+		case bool:
+			return b, true
+		case float64:
+			return b != 0, true
+		case string:
+			p, err := strconv.ParseBool(b)
+			return p, err == nil
+		}
+	case schema.TypeInt:
+		switch n := v.(type) {
+		case int:
+			return n, true
+		case float64:
+			return int(n), true
+		case string:
+			p, err := strconv.Atoi(n)
+			return p, err == nil
+		}
+	case schema.TypeFloat:
+		switch n := v.(type) {
+		case int:
+			return float64(n), true
+		case float64:
+			return n, true
+		case string:
+			p, err := strconv.ParseFloat(n, 64)
+			return p, err == nil
+		}
+	case schema.TypeString:
+		switch s := v.(type) {
+		case string:
+			return s, true
+		default:
+			b, err := json.Marshal(s)
+			return string(b), err == nil
+		}
+	}
+	return nil, false
+}
+
+// metaHashKey returns a canonical string for a metadata block, for use in
+// the hash of the set it is in.
+func metaHashKey(v interface{}) string {
+	block := metaBlock(v)
+	if block == nil {
+		return ""
+	}
+	m, err := metaBlockToAPI(block)
+	if err != nil || len(m) == 0 {
+		return ""
 	}
+	b, _ := json.Marshal(m)
+	return string(b)
 }
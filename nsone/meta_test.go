@@ -0,0 +1,144 @@
+package nsone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func TestMetaBlockRoundTrip(t *testing.T) {
+	block := map[string]interface{}{
+		"up":           false,
+		"weight":       "0.5",
+		"priority":     "2",
+		"country":      []interface{}{"US", "CA"},
+		"asn":          []interface{}{3320, 64512},
+		"ip_prefixes":  []interface{}{"10.0.0.0/8"},
+		"note":         "primary",
+		"loadavg_feed": "feed123",
+	}
+	m, err := metaBlockToAPI(block)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]interface{}{
+		"up":          false,
+		"weight":      0.5,
+		"priority":    2,
+		"country":     []interface{}{"US", "CA"},
+		"asn":         []interface{}{3320, 64512},
+		"ip_prefixes": []interface{}{"10.0.0.0/8"},
+		"note":        "primary",
+		"loadavg":     map[string]interface{}{"feed": "feed123"},
+	}
+	if !reflect.DeepEqual(m, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, m)
+	}
+
+	// As decoded from the API's JSON.
+	api := map[string]interface{}{
+		"up":          false,
+		"weight":      0.5,
+		"priority":    float64(2),
+		"country":     []interface{}{"US", "CA"},
+		"asn":         []interface{}{float64(3320), float64(64512)},
+		"ip_prefixes": []interface{}{"10.0.0.0/8"},
+		"note":        "primary",
+		"loadavg":     map[string]interface{}{"feed": "feed123"},
+		"unknown":     "dropped",
+	}
+	if back := metaAPIToBlock(api); !reflect.DeepEqual(back, block) {
+		t.Fatalf("expected %#v, got %#v", block, back)
+	}
+	if metaHashKey([]interface{}{block}) != metaHashKey([]interface{}{metaAPIToBlock(api)}) {
+		t.Fatalf("expected the read back block to hash the same")
+	}
+}
+
+func TestMetaBlockToAPIUnset(t *testing.T) {
+	m, err := metaBlockToAPI(map[string]interface{}{
+		"up":       true,
+		"weight":   "",
+		"country":  []interface{}{},
+		"note":     "",
+		"up_feed":  "",
+		"priority": "",
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(m) != 0 {
+		t.Fatalf("expected nothing to be sent, got %#v", m)
+	}
+
+	m, _ = metaBlockToAPI(map[string]interface{}{"up": true, "up_feed": "feed123"})
+	if !reflect.DeepEqual(m, map[string]interface{}{"up": map[string]interface{}{"feed": "feed123"}}) {
+		t.Fatalf("expected up_feed to be sent, got %#v", m)
+	}
+	if _, err := metaBlockToAPI(map[string]interface{}{"weight": "2", "weight_feed": "feed123"}); err == nil {
+		t.Fatalf("expected an error for both weight and weight_feed")
+	}
+}
+
+func TestMetaBlockToAPIZero(t *testing.T) {
+	block := map[string]interface{}{
+		"up":        true,
+		"latitude":  "0",
+		"longitude": "-0.1",
+		"priority":  "0",
+	}
+	m, err := metaBlockToAPI(block)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]interface{}{"latitude": 0.0, "longitude": -0.1, "priority": 0}
+	if !reflect.DeepEqual(m, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, m)
+	}
+	if back := metaAPIToBlock(map[string]interface{}{"latitude": 0.0, "longitude": -0.1, "priority": 0.0}); !reflect.DeepEqual(back, block) {
+		t.Fatalf("expected %#v, got %#v", block, back)
+	}
+	if metaHashKey([]interface{}{block}) == metaHashKey([]interface{}{map[string]interface{}{"up": true, "longitude": "-0.1"}}) {
+		t.Fatalf("expected a zero latitude and priority to change the hash")
+	}
+
+	if _, err := metaBlockToAPI(map[string]interface{}{"latitude": "north"}); err == nil {
+		t.Fatalf("expected an error for a latitude that is not a number")
+	}
+	if _, errs := validateMetaNumber(metaField{name: "latitude", typ: schema.TypeFloat, validate: validation.FloatBetween(-90, 90)})("91", "latitude"); len(errs) == 0 {
+		t.Fatalf("expected an error for a latitude out of range")
+	}
+}
+
+func TestMetaAPIToBlockLegacyValues(t *testing.T) {
+	block := metaAPIToBlock(map[string]interface{}{
+		"up":      "1",
+		"weight":  "10",
+		"country": "US, CA",
+	})
+	if block["up"] != true || block["weight"] != "10" {
+		t.Fatalf("bad block: %#v", block)
+	}
+	if !reflect.DeepEqual(block["country"], []interface{}{"US", "CA"}) {
+		t.Fatalf("bad country: %#v", block["country"])
+	}
+}
+
+func TestRecordMetaZeroSent(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, recordResource().Schema, testRecordConfig(map[string]interface{}{
+		"regions": []interface{}{map[string]interface{}{
+			"name": "equator",
+			"meta": []interface{}{map[string]interface{}{"latitude": "0", "longitude": "0"}},
+		}},
+	}))
+	r := NewRecord("terraform.io", "test.terraform.io", "A")
+	if err := resourceDataToRecord(r, d); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]interface{}{"latitude": 0.0, "longitude": 0.0}
+	if m := r.Regions["equator"].Meta; !reflect.DeepEqual(m, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, m)
+	}
+}
@@ -1,9 +1,11 @@
 package nsone
 
 import (
+	"time"
+
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
-	nsone "gopkg.in/sarguru/ns1-go.v18"
 )
 
 func Provider() terraform.ResourceProvider {
@@ -15,6 +17,64 @@ func Provider() terraform.ResourceProvider {
 				DefaultFunc: schema.EnvDefaultFunc("NSONE_APIKEY", nil),
 				Description: descriptions["api_key"],
 			},
+			"endpoint": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("NSONE_ENDPOINT", defaultEndpoint),
+				Description: descriptions["endpoint"],
+			},
+			"ignore_ssl": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("NSONE_IGNORE_SSL", false),
+				Description: descriptions["ignore_ssl"],
+			},
+			"ca_cert_file": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("NSONE_CA_CERT_FILE", ""),
+				Description: descriptions["ca_cert_file"],
+			},
+			"http_proxy": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("NSONE_HTTP_PROXY", ""),
+				Description: descriptions["http_proxy"],
+			},
+			"debug": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("NSONE_DEBUG", false),
+				Description: descriptions["debug"],
+			},
+			"rate_limit_strategy": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("NSONE_RATE_LIMIT_STRATEGY", rateLimitSleep),
+				Description:  descriptions["rate_limit_strategy"],
+				ValidateFunc: validation.StringInSlice(rateLimitStrategies, false),
+			},
+			"max_parallel_requests": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("NSONE_MAX_PARALLEL_REQUESTS", 0),
+				Description:  descriptions["max_parallel_requests"],
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"retry_max": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("NSONE_RETRY_MAX", 3),
+				Description:  descriptions["retry_max"],
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"retry_max_wait": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("NSONE_RETRY_MAX_WAIT", 30),
+				Description:  descriptions["retry_max_wait"],
+				ValidateFunc: validation.IntAtLeast(1),
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"nsone_zone":          zoneResource(),
@@ -25,22 +85,52 @@ func Provider() terraform.ResourceProvider {
 			"nsone_user":          userResource(),
 			"nsone_apikey":        apikeyResource(),
 			"nsone_team":          teamResource(),
+			"nsone_tsig_key":      tsigKeyResource(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"nsone_zone":              zoneDataSource(),
+			"nsone_record":            recordDataSource(),
+			"nsone_zones":             zonesDataSource(),
+			"nsone_records":           recordsDataSource(),
+			"nsone_record_simulation": recordSimulationDataSource(),
 		},
 		ConfigureFunc: nsoneConfigure,
 	}
 }
 
 func nsoneConfigure(d *schema.ResourceData) (interface{}, error) {
-	n := nsone.New(d.Get("apikey").(string))
-	n.Debug()
-	n.RateLimitStrategySleep()
-	return n, nil
+	config := Config{
+		Key:        d.Get("apikey").(string),
+		Endpoint:   d.Get("endpoint").(string),
+		IgnoreSSL:  d.Get("ignore_ssl").(bool),
+		CACertFile: d.Get("ca_cert_file").(string),
+		HTTPProxy:  d.Get("http_proxy").(string),
+		Debug:      d.Get("debug").(bool),
+
+		RateLimitStrategy:   d.Get("rate_limit_strategy").(string),
+		MaxParallelRequests: d.Get("max_parallel_requests").(int),
+
+		RetryMax:     d.Get("retry_max").(int),
+		RetryMaxWait: time.Duration(d.Get("retry_max_wait").(int)) * time.Second,
+	}
+	return config.Client()
 }
 
 var descriptions map[string]string
 
 func init() {
 	descriptions = map[string]string{
-		"api_key": "The nsone API key, this is required",
+		"api_key":      "The nsone API key, this is required",
+		"endpoint":     "The URL of the NS1 API to talk to, defaults to the public API",
+		"ignore_ssl":   "Skip verification of the API endpoint's TLS certificate",
+		"ca_cert_file": "Path to a PEM file of extra CA certificates to trust for the API endpoint",
+		"http_proxy":   "URL of an HTTP proxy to reach the API through, overrides the HTTP(S)_PROXY environment variables",
+		"debug":        "Log every API request and response (with secrets redacted) at DEBUG level",
+
+		"rate_limit_strategy":   "How to stay within the API rate limit: sleep (pace requests evenly), concurrent (burst, then wait) or none",
+		"max_parallel_requests": "The most API requests to have in flight at once, 0 for no limit",
+
+		"retry_max":      "How many times to retry a request that failed with a 429, a 5xx or a network error",
+		"retry_max_wait": "The longest time, in seconds, to wait between retries",
 	}
 }
@@ -0,0 +1,135 @@
+package nsone
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	nsone "gopkg.in/sarguru/ns1-go.v18"
+)
+
+// Rate limit strategies accepted by the rate_limit_strategy provider setting.
+const (
+	// rateLimitSleep paces requests evenly at the rate the API allows.
+	rateLimitSleep = "sleep"
+	// rateLimitConcurrent lets requests burst until the API's remaining
+	// allowance is used up, then waits for it to refill.
+	rateLimitConcurrent = "concurrent"
+	// rateLimitNone never waits; 429s are left to the retry logic.
+	rateLimitNone = "none"
+)
+
+var rateLimitStrategies = []string{rateLimitSleep, rateLimitConcurrent, rateLimitNone}
+
+// rateLimiter is a token bucket shared by every request made through a
+// Client, so that Terraform's parallel resource operations draw on the one
+// API allowance instead of each sleeping independently. The bucket refills
+// at X-Ratelimit-Limit per X-Ratelimit-Period and is kept in step with the
+// X-Ratelimit-Remaining the API reports. Until the first response arrives
+// the limits are unknown and requests are not delayed.
+type rateLimiter struct {
+	strategy string
+	// slots caps the number of requests in flight; nil means no cap.
+	slots chan struct{}
+
+	mu       sync.Mutex
+	known    bool
+	tokens   float64
+	capacity float64
+	rate     float64 // tokens per second
+	last     time.Time
+}
+
+func newRateLimiter(strategy string, maxParallel int) (*rateLimiter, error) {
+	switch strategy {
+	case "":
+		strategy = rateLimitSleep
+	case rateLimitSleep, rateLimitConcurrent, rateLimitNone:
+	default:
+		return nil, fmt.Errorf("unknown rate limit strategy %q", strategy)
+	}
+	l := &rateLimiter{strategy: strategy}
+	if maxParallel > 0 {
+		l.slots = make(chan struct{}, maxParallel)
+	}
+	return l, nil
+}
+
+// acquire blocks until a request may be sent. Every acquire must be paired
+// with a release.
+func (l *rateLimiter) acquire() {
+	if l.slots != nil {
+		l.slots <- struct{}{}
+	}
+	if wait := l.reserve(time.Now()); wait > 0 {
+		log.Printf("[DEBUG] nsone: rate limiting (%s), sleeping %s", l.strategy, wait)
+		time.Sleep(wait)
+	}
+}
+
+// release records the rate limit the API reported for a finished request,
+// if any, and frees its parallelism slot.
+func (l *rateLimiter) release(rl nsone.RateLimit, ok bool) {
+	if ok {
+		l.update(rl, time.Now())
+	}
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// reserve takes a token, returning how long the caller must wait for it.
+// Tokens may go negative, which queues later callers behind earlier ones.
+func (l *rateLimiter) reserve(now time.Time) time.Duration {
+	if l.strategy == rateLimitNone {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.known {
+		return 0
+	}
+	l.refill(now)
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+func (l *rateLimiter) update(rl nsone.RateLimit, now time.Time) {
+	if rl.Limit <= 0 || rl.Period <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rate = float64(rl.Limit) / float64(rl.Period)
+	if l.strategy == rateLimitSleep {
+		// No bursting: one request per 1/rate.
+		l.capacity = 1
+	} else {
+		l.capacity = float64(rl.Limit)
+	}
+	if !l.known {
+		l.known = true
+		l.tokens = l.capacity
+		l.last = now
+	}
+	l.refill(now)
+	// The API is the authority on what is left; tokens already reserved by
+	// waiting requests stay reserved.
+	if remaining := float64(rl.Remaining); l.tokens > remaining {
+		l.tokens = remaining
+	}
+}
+
+func (l *rateLimiter) refill(now time.Time) {
+	if elapsed := now.Sub(l.last).Seconds(); elapsed > 0 {
+		l.tokens += elapsed * l.rate
+		if l.tokens > l.capacity {
+			l.tokens = l.capacity
+		}
+	}
+	l.last = now
+}
@@ -0,0 +1,84 @@
+package nsone
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	nsone "gopkg.in/sarguru/ns1-go.v18"
+)
+
+func TestRateLimiter_sleep(t *testing.T) {
+	l, _ := newRateLimiter(rateLimitSleep, 0)
+	now := time.Now()
+	if wait := l.reserve(now); wait != 0 {
+		t.Fatalf("should not wait before limits are known, waited %s", wait)
+	}
+
+	// 10 requests per second, paced evenly.
+	l.update(nsone.RateLimit{Limit: 10, Remaining: 9, Period: 1}, now)
+	if wait := l.reserve(now); wait != 0 {
+		t.Fatalf("first request should not wait, waited %s", wait)
+	}
+	if wait := l.reserve(now); wait != 100*time.Millisecond {
+		t.Fatalf("second request should wait 100ms, waited %s", wait)
+	}
+	if wait := l.reserve(now); wait != 200*time.Millisecond {
+		t.Fatalf("third request should queue behind the second, waited %s", wait)
+	}
+}
+
+func TestRateLimiter_concurrent(t *testing.T) {
+	l, _ := newRateLimiter(rateLimitConcurrent, 0)
+	now := time.Now()
+	l.update(nsone.RateLimit{Limit: 10, Remaining: 3, Period: 1}, now)
+	for i := 0; i < 3; i++ {
+		if wait := l.reserve(now); wait != 0 {
+			t.Fatalf("request %d should burst, waited %s", i, wait)
+		}
+	}
+	if wait := l.reserve(now); wait != 100*time.Millisecond {
+		t.Fatalf("request after the allowance is used should wait 100ms, waited %s", wait)
+	}
+}
+
+func TestRateLimiter_none(t *testing.T) {
+	l, _ := newRateLimiter(rateLimitNone, 0)
+	now := time.Now()
+	l.update(nsone.RateLimit{Limit: 1, Remaining: 0, Period: 60}, now)
+	if wait := l.reserve(now); wait != 0 {
+		t.Fatalf("none strategy should never wait, waited %s", wait)
+	}
+
+	if _, err := newRateLimiter("bogus", 0); err == nil {
+		t.Fatalf("expected error for unknown strategy")
+	}
+}
+
+func TestRateLimiter_maxParallel(t *testing.T) {
+	l, _ := newRateLimiter(rateLimitNone, 2)
+	var inFlight, maxInFlight int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.acquire()
+			n := atomic.AddInt32(&inFlight, 1)
+			for {
+				m := atomic.LoadInt32(&maxInFlight)
+				if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&inFlight, -1)
+			l.release(nsone.RateLimit{}, false)
+		}()
+	}
+	wg.Wait()
+	if maxInFlight > 2 {
+		t.Fatalf("max_parallel_requests not honoured: %d in flight", maxInFlight)
+	}
+}
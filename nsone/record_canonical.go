@@ -0,0 +1,152 @@
+package nsone
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// hostnameTypes are the record types whose answer is a single host name.
+var hostnameTypes = map[string]bool{
+	"ALIAS": true,
+	"CNAME": true,
+	"DNAME": true,
+	"NS":    true,
+	"PTR":   true,
+}
+
+// canonicalHostname returns a host name as the API returns it: lower case,
+// without a trailing dot. The root, ".", is left as it is.
+func canonicalHostname(v string) string {
+	if v == "." {
+		return v
+	}
+	return strings.ToLower(strings.TrimSuffix(v, "."))
+}
+
+// canonicalIP returns an IP address in its shortest form, e.g. 2001:db8::1
+// for 2001:0db8:0:0::1, or v unchanged if it is not an IP address. An IPv4
+// address written in IPv6 form stays in IPv6 form.
+func canonicalIP(v string) string {
+	ip := net.ParseIP(v)
+	if ip == nil {
+		return v
+	}
+	if ip.To4() != nil && strings.Contains(v, ":") {
+		return strings.ToLower(v)
+	}
+	return ip.String()
+}
+
+// canonicalRdata returns the rdata of an answer of a record of type t in the
+// form the API returns it, so that the answer given and the answer read back
+// compare equal. Rdata that does not fit the type is returned unchanged.
+func canonicalRdata(t string, rdata []string) []string {
+	switch {
+	case t == "A" || t == "AAAA":
+		if len(rdata) == 1 {
+			return []string{canonicalIP(rdata[0])}
+		}
+	case hostnameTypes[t]:
+		if len(rdata) == 1 {
+			return []string{canonicalHostname(rdata[0])}
+		}
+	default:
+		f, ok := rdataFormats[t]
+		if !ok {
+			break
+		}
+		if len(rdata) == 1 && len(f.fields) > 1 {
+			rdata = strings.SplitN(rdata[0], " ", len(f.fields))
+		}
+		if len(rdata) != len(f.fields) {
+			break
+		}
+		c := make([]string, len(rdata))
+		for i, field := range f.fields {
+			c[i] = rdata[i]
+			if field.hostname {
+				c[i] = canonicalHostname(rdata[i])
+			}
+		}
+		return c
+	}
+	return rdata
+}
+
+// canonicalAnswer returns an answer string of a record of type t in the form
+// the API returns it.
+func canonicalAnswer(t, v string) string {
+	if isTXT(t) {
+		return v
+	}
+	return strings.Join(canonicalRdata(t, answerRdata(t, map[string]interface{}{"answer": v})), " ")
+}
+
+// canonicalKey canonicalises the parts of an answer's rdata that are IP
+// addresses, for hashing an answer without knowing the type of its record.
+// Host names are left as they are, since a TXT answer can look like one and
+// differ only in case; the spelling of a host name the API changes is kept
+// when the answer is read back instead, see keepAnswerSpelling.
+func canonicalKey(v string) string {
+	tokens := strings.Split(v, " ")
+	for i, token := range tokens {
+		tokens[i] = canonicalIP(token)
+	}
+	return strings.Join(tokens, " ")
+}
+
+// keepAnswerSpelling returns answer, read back from the API for a record of
+// type t, with the rdata of the prior answer in place of its own if the two
+// are the same answer spelled differently, e.g. WWW.example.com. as given
+// and www.example.com as returned, so that the answer keeps its place in
+// the answers set.
+func keepAnswerSpelling(t string, answer map[string]interface{}, prior []interface{}) map[string]interface{} {
+	key := strings.Join(canonicalRdata(t, answerRdata(t, answer)), " ")
+	for _, raw := range prior {
+		p, ok := raw.(map[string]interface{})
+		if !ok || strings.Join(canonicalRdata(t, answerRdata(t, p)), " ") != key {
+			continue
+		}
+		answer["answer"] = p["answer"]
+		answer["answer_parts"] = p["answer_parts"]
+		for _, f := range rdataFormats {
+			answer[f.block] = p[f.block]
+		}
+		return answer
+	}
+	return answer
+}
+
+// suppressEquivalentAnswer suppresses the diff between two spellings of the
+// same answer, such as 2001:0db8:0:0::1 and 2001:db8::1, or www.example.com.
+// and WWW.example.com.
+func suppressEquivalentAnswer(k, old, new string, d *schema.ResourceData) bool {
+	t, _ := d.Get("type").(string)
+	return canonicalAnswer(t, old) == canonicalAnswer(t, new)
+}
+
+// suppressEquivalentHostname suppresses the diff between two spellings of the
+// same host name.
+func suppressEquivalentHostname(k, old, new string, d *schema.ResourceData) bool {
+	return canonicalHostname(old) == canonicalHostname(new)
+}
+
+// validateAnswerAddress checks that the answer of an A record is an IPv4
+// address, and of an AAAA record an IPv6 address.
+func validateAnswerAddress(t string, a map[string]interface{}) error {
+	if t != "A" && t != "AAAA" {
+		return nil
+	}
+	v, _ := a["answer"].(string)
+	ip := net.ParseIP(v)
+	switch {
+	case t == "A" && (ip == nil || ip.To4() == nil || strings.Contains(v, ":")):
+		return fmt.Errorf("answer %q of an A record must be an IPv4 address", v)
+	case t == "AAAA" && (ip == nil || !strings.Contains(v, ":")):
+		return fmt.Errorf("answer %q of an AAAA record must be an IPv6 address", v)
+	}
+	return nil
+}
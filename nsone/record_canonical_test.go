@@ -0,0 +1,74 @@
+package nsone
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCanonicalRdata(t *testing.T) {
+	cases := []struct {
+		t        string
+		rdata    []string
+		expected []string
+	}{
+		{"A", []string{"192.0.2.1"}, []string{"192.0.2.1"}},
+		{"AAAA", []string{"2001:0DB8:0:0::1"}, []string{"2001:db8::1"}},
+		{"AAAA", []string{"::FFFF:192.0.2.1"}, []string{"::ffff:192.0.2.1"}},
+		{"CNAME", []string{"WWW.Example.com."}, []string{"www.example.com"}},
+		{"MX", []string{"10 MX.Example.com."}, []string{"10", "mx.example.com"}},
+		{"MX", []string{"0", "."}, []string{"0", "."}},
+		{"SRV", []string{"1", "2", "443", "Sip.Example.com."}, []string{"1", "2", "443", "sip.example.com"}},
+		{"NAPTR", []string{"100", "10", "U", "E2U+sip", "!^.*$!sip:Info@Example.com!", "."}, []string{"100", "10", "U", "E2U+sip", "!^.*$!sip:Info@Example.com!", "."}},
+		{"TXT", []string{"Hello.World."}, []string{"Hello.World."}},
+	}
+	for _, c := range cases {
+		if got := canonicalRdata(c.t, c.rdata); !reflect.DeepEqual(got, c.expected) {
+			t.Errorf("%s %v: expected %v, got %v", c.t, c.rdata, c.expected, got)
+		}
+	}
+}
+
+func TestAnswerKeyIsCanonical(t *testing.T) {
+	pairs := [][2]map[string]interface{}{
+		{{"answer": "2001:0db8:0:0::1"}, {"answer": "2001:db8::1"}},
+		{
+			{"mx": []interface{}{map[string]interface{}{"preference": 10, "exchange": "mx.example.com"}}},
+			{"answer": "10 mx.example.com"},
+		},
+	}
+	for _, p := range pairs {
+		if answerKey(p[0]) != answerKey(p[1]) {
+			t.Errorf("expected %v and %v to have the same key, got %q and %q", p[0], p[1], answerKey(p[0]), answerKey(p[1]))
+		}
+	}
+	// The type of the record is not known when hashing, so text that looks
+	// like a host name is not lower-cased.
+	if answerKey(map[string]interface{}{"answer": "Hello.World"}) == answerKey(map[string]interface{}{"answer": "hello.world"}) {
+		t.Errorf("expected answers differing in case to have different keys")
+	}
+	if answerKey(map[string]interface{}{"answer": "v=spf1 include:_SPF.example.com -all"}) != "v=spf1 include:_SPF.example.com -all" {
+		t.Errorf("expected a TXT answer to be left as it is")
+	}
+}
+
+func TestValidateAnswerAddress(t *testing.T) {
+	cases := []struct {
+		t, answer string
+		ok        bool
+	}{
+		{"A", "192.0.2.1", true},
+		{"A", "2001:db8::1", false},
+		{"A", "::ffff:192.0.2.1", false},
+		{"A", "www.example.com", false},
+		{"AAAA", "2001:db8::1", true},
+		{"AAAA", "::ffff:192.0.2.1", true},
+		{"AAAA", "192.0.2.1", false},
+		{"CNAME", "www.example.com", true},
+	}
+	for _, c := range cases {
+		err := validateAnswerAddress(c.t, map[string]interface{}{"answer": c.answer})
+		if (err == nil) != c.ok {
+			t.Errorf("%s %s: expected ok %t, got %v", c.t, c.answer, c.ok, err)
+		}
+	}
+}
@@ -0,0 +1,329 @@
+package nsone
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// rdataField is one field of the rdata of a structured answer. Fields are
+// either strings or ints; optional fields may be left empty. Host name fields
+// are compared ignoring case and a trailing dot.
+type rdataField struct {
+	name     string
+	typ      schema.ValueType
+	validate schema.SchemaValidateFunc
+	optional bool
+	hostname bool
+}
+
+// rdataFormat describes the rdata of a record type as a block of named
+// fields, which can be given in an answer instead of the space separated
+// answer string.
+type rdataFormat struct {
+	block  string
+	fields []rdataField
+}
+
+var hexRegexp = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+
+var validateHex = validation.StringMatch(hexRegexp, "must be a hex string")
+
+var validateUint16 = validation.IntBetween(0, 65535)
+
+var validateDomain = validation.StringMatch(regexp.MustCompile(`^(\.|[^\s.]+(\.[^\s.]+)*\.?)$`), "must be a domain name")
+
+// rdataFormats are the record types with structured answers, by type.
+var rdataFormats = map[string]rdataFormat{
+	"MX": {"mx", []rdataField{
+		{name: "preference", typ: schema.TypeInt, validate: validateUint16},
+		{name: "exchange", typ: schema.TypeString, validate: validateDomain, hostname: true},
+	}},
+	"SRV": {"srv", []rdataField{
+		{name: "priority", typ: schema.TypeInt, validate: validateUint16},
+		{name: "weight", typ: schema.TypeInt, validate: validateUint16},
+		{name: "port", typ: schema.TypeInt, validate: validateUint16},
+		{name: "target", typ: schema.TypeString, validate: validateDomain, hostname: true},
+	}},
+	"NAPTR": {"naptr", []rdataField{
+		{name: "order", typ: schema.TypeInt, validate: validateUint16},
+		{name: "preference", typ: schema.TypeInt, validate: validateUint16},
+		{name: "flags", typ: schema.TypeString, validate: validation.StringMatch(regexp.MustCompile(`^[A-Za-z0-9]*$`), "must be alphanumeric"), optional: true},
+		{name: "service", typ: schema.TypeString, optional: true},
+		{name: "regexp", typ: schema.TypeString, optional: true},
+		{name: "replacement", typ: schema.TypeString, validate: validateDomain, hostname: true},
+	}},
+	"AFSDB": {"afsdb", []rdataField{
+		{name: "subtype", typ: schema.TypeInt, validate: validation.IntBetween(1, 2)},
+		{name: "hostname", typ: schema.TypeString, validate: validateDomain, hostname: true},
+	}},
+	"HINFO": {"hinfo", []rdataField{
+		{name: "cpu", typ: schema.TypeString},
+		{name: "os", typ: schema.TypeString},
+	}},
+	"RP": {"rp", []rdataField{
+		{name: "mailbox", typ: schema.TypeString, validate: validateDomain, hostname: true},
+		{name: "txt", typ: schema.TypeString, validate: validateDomain, hostname: true},
+	}},
+	"CAA": {"caa", []rdataField{
+		{name: "flags", typ: schema.TypeInt, validate: validation.IntBetween(0, 255)},
+		{name: "tag", typ: schema.TypeString, validate: validation.StringInSlice([]string{"issue", "issuewild", "iodef"}, false)},
+		{name: "value", typ: schema.TypeString},
+	}},
+	"SSHFP": {"sshfp", []rdataField{
+		{name: "algorithm", typ: schema.TypeInt, validate: validation.IntBetween(1, 6)},
+		{name: "fingerprint_type", typ: schema.TypeInt, validate: validation.IntBetween(1, 2)},
+		{name: "fingerprint", typ: schema.TypeString, validate: validateHex},
+	}},
+	"TLSA": {"tlsa", []rdataField{
+		{name: "cert_usage", typ: schema.TypeInt, validate: validation.IntBetween(0, 3)},
+		{name: "selector", typ: schema.TypeInt, validate: validation.IntBetween(0, 1)},
+		{name: "matching_type", typ: schema.TypeInt, validate: validation.IntBetween(0, 2)},
+		{name: "cert_data", typ: schema.TypeString, validate: validateHex},
+	}},
+	"DS": {"ds", []rdataField{
+		{name: "key_tag", typ: schema.TypeInt, validate: validateUint16},
+		{name: "algorithm", typ: schema.TypeInt, validate: validation.IntBetween(0, 255)},
+		{name: "digest_type", typ: schema.TypeInt, validate: validation.IntBetween(0, 255)},
+		{name: "digest", typ: schema.TypeString, validate: validateHex},
+	}},
+	"CERT": {"cert", []rdataField{
+		{name: "type", typ: schema.TypeString},
+		{name: "key_tag", typ: schema.TypeInt, validate: validateUint16},
+		{name: "algorithm", typ: schema.TypeString},
+		{name: "certificate", typ: schema.TypeString},
+	}},
+	"URLFWD": {"urlfwd", []rdataField{
+		{name: "from", typ: schema.TypeString},
+		{name: "to", typ: schema.TypeString},
+		// 0 = 301, 1 = 302, 2 = masking
+		{name: "redirect_type", typ: schema.TypeInt, validate: validation.IntBetween(0, 2)},
+		// 0 = none, 1 = capture, 2 = append, 3 = capture and append
+		{name: "path_forwarding", typ: schema.TypeInt, validate: validation.IntBetween(0, 3)},
+		// 0 = off, 1 = on
+		{name: "query_forwarding", typ: schema.TypeInt, validate: validation.IntBetween(0, 1)},
+	}},
+}
+
+func (f rdataFormat) schema() *schema.Schema {
+	s := make(map[string]*schema.Schema, len(f.fields))
+	for _, field := range f.fields {
+		s[field.name] = &schema.Schema{
+			Type:         field.typ,
+			Required:     !field.optional,
+			Optional:     field.optional,
+			ValidateFunc: field.validate,
+		}
+		if field.hostname {
+			s[field.name].DiffSuppressFunc = suppressEquivalentHostname
+		}
+	}
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Computed: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: s,
+		},
+	}
+}
+
+// toRdata turns a structured answer block into rdata.
+func (f rdataFormat) toRdata(block map[string]interface{}) []string {
+	rdata := make([]string, len(f.fields))
+	for i, field := range f.fields {
+		switch v := block[field.name].(type) {
+		case int:
+			rdata[i] = strconv.Itoa(v)
+		case string:
+			rdata[i] = v
+		}
+	}
+	return rdata
+}
+
+// fromRdata turns rdata into a structured answer block, or nil if the rdata
+// does not fit the format. Rdata given as a single space separated string is
+// split; any extra elements are joined into the last field. The elements are
+// kept as they are otherwise, so e.g. a NAPTR regexp containing spaces
+// round-trips exactly.
+func (f rdataFormat) fromRdata(rdata []string) map[string]interface{} {
+	if len(rdata) == 1 && len(f.fields) > 1 {
+		rdata = strings.SplitN(rdata[0], " ", len(f.fields))
+	}
+	if len(rdata) < len(f.fields) {
+		return nil
+	}
+	if len(rdata) > len(f.fields) {
+		last := len(f.fields) - 1
+		rdata = append(rdata[:last:last], strings.Join(rdata[last:], " "))
+	}
+	block := make(map[string]interface{}, len(f.fields))
+	for i, field := range f.fields {
+		if field.typ == schema.TypeInt {
+			n, err := strconv.Atoi(rdata[i])
+			if err != nil {
+				return nil
+			}
+			block[field.name] = n
+		} else {
+			block[field.name] = rdata[i]
+		}
+	}
+	return block
+}
+
+// maxCharacterString is the longest a single character-string in the rdata
+// of a TXT or SPF record can be, in bytes.
+const maxCharacterString = 255
+
+// isTXT reports whether records of type t have rdata made of
+// character-strings, which are sent and read back as answer_parts.
+func isTXT(t string) bool {
+	return t == "TXT" || t == "SPF"
+}
+
+func validateCharacterString(v interface{}, k string) (ws []string, es []error) {
+	if len(v.(string)) > maxCharacterString {
+		es = append(es, fmt.Errorf("%q must be at most %d bytes long", k, maxCharacterString))
+	}
+	return
+}
+
+// chunkTXT splits a TXT value into character-strings of at most 255 bytes,
+// without splitting a multi-byte character.
+func chunkTXT(v string) []string {
+	if len(v) <= maxCharacterString {
+		return []string{v}
+	}
+	var parts []string
+	for len(v) > maxCharacterString {
+		i := maxCharacterString
+		for i > 0 && !utf8.RuneStart(v[i]) {
+			i--
+		}
+		parts = append(parts, v[:i])
+		v = v[i:]
+	}
+	return append(parts, v)
+}
+
+// answerParts returns the answer_parts set in an answer.
+func answerParts(a map[string]interface{}) []string {
+	raw, _ := a["answer_parts"].([]interface{})
+	parts := make([]string, 0, len(raw))
+	for _, p := range raw {
+		s, _ := p.(string)
+		parts = append(parts, s)
+	}
+	return parts
+}
+
+// plannedAnswer returns the ordered answer at key without the forms of it
+// that are left over from the state. answer, answer_parts and the structured
+// blocks are computed, so a form not in the configuration keeps the value
+// read for the old answer; once any form changes, those that did not are
+// stale.
+func plannedAnswer(hasChange func(string) bool, key string, a map[string]interface{}) map[string]interface{} {
+	forms := []string{"answer", "answer_parts"}
+	for _, f := range rdataFormats {
+		forms = append(forms, f.block)
+	}
+	var stale []string
+	changed := false
+	for _, f := range forms {
+		if hasChange(key + "." + f) {
+			changed = true
+		} else {
+			stale = append(stale, f)
+		}
+	}
+	if !changed || len(stale) == 0 {
+		return a
+	}
+	planned := make(map[string]interface{}, len(a))
+	for k, v := range a {
+		planned[k] = v
+	}
+	for _, f := range stale {
+		delete(planned, f)
+	}
+	return planned
+}
+
+// answerKey returns the value an answer is identified by: its rdata, with
+// the character-strings of a TXT or SPF answer concatenated, as resolvers
+// do, and IP addresses and host names in canonical form. This is the same
+// whichever form the answer was given in, and for the answer read back from
+// the API.
+func answerKey(a map[string]interface{}) string {
+	if f, block, ok := structuredAnswer(a); ok {
+		return canonicalKey(strings.Join(f.toRdata(block), " "))
+	}
+	if parts := answerParts(a); len(parts) > 0 {
+		return canonicalKey(strings.Join(parts, ""))
+	}
+	v, _ := a["answer"].(string)
+	return canonicalKey(v)
+}
+
+// structuredAnswer returns the structured answer block set in an answer, and
+// its format, if there is one.
+func structuredAnswer(a map[string]interface{}) (rdataFormat, map[string]interface{}, bool) {
+	for _, f := range rdataFormats {
+		if l, ok := a[f.block].([]interface{}); ok && len(l) > 0 && l[0] != nil {
+			return f, l[0].(map[string]interface{}), true
+		}
+	}
+	return rdataFormat{}, nil, false
+}
+
+// answerRdata returns the rdata for an answer of a record of type t, from
+// its structured block if it has one, otherwise from its answer string.
+func answerRdata(t string, a map[string]interface{}) []string {
+	if f, block, ok := structuredAnswer(a); ok {
+		return f.toRdata(block)
+	}
+	v, _ := a["answer"].(string)
+	if isTXT(t) {
+		if parts := answerParts(a); len(parts) > 0 {
+			return parts
+		}
+		return chunkTXT(v)
+	}
+	if f, ok := rdataFormats[t]; ok {
+		return strings.SplitN(v, " ", len(f.fields))
+	}
+	return strings.Split(v, " ")
+}
+
+// validateStructuredAnswer checks that an answer of a record of type t only
+// uses the structured block or answer_parts where the type allows.
+func validateStructuredAnswer(t string, a map[string]interface{}) error {
+	if parts := answerParts(a); len(parts) > 0 {
+		if !isTXT(t) {
+			return fmt.Errorf("answer_parts cannot be used in a %s record", t)
+		}
+		if v, _ := a["answer"].(string); v != "" && v != strings.Join(parts, "") {
+			return fmt.Errorf("answer and answer_parts disagree, only one of them should be set")
+		}
+		return nil
+	}
+	f, _, ok := structuredAnswer(a)
+	if !ok {
+		if v, _ := a["answer"].(string); v == "" {
+			return fmt.Errorf("answer must be set for a %s record", t)
+		}
+		return nil
+	}
+	if rdataFormats[t].block != f.block {
+		return fmt.Errorf("a %s block cannot be used in a %s record", f.block, t)
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package nsone
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	nsone "gopkg.in/sarguru/ns1-go.v18"
+)
+
+func TestRdataRoundTrip(t *testing.T) {
+	cases := map[string]struct {
+		block map[string]interface{}
+		rdata []string
+	}{
+		"MX": {
+			map[string]interface{}{"preference": 10, "exchange": "mx1.example.com."},
+			[]string{"10", "mx1.example.com."},
+		},
+		"SRV": {
+			map[string]interface{}{"priority": 10, "weight": 20, "port": 5060, "target": "sip.example.com."},
+			[]string{"10", "20", "5060", "sip.example.com."},
+		},
+		"AFSDB": {
+			map[string]interface{}{"subtype": 1, "hostname": "afsdb.example.com."},
+			[]string{"1", "afsdb.example.com."},
+		},
+		"HINFO": {
+			map[string]interface{}{"cpu": "x86", "os": "linux"},
+			[]string{"x86", "linux"},
+		},
+		"RP": {
+			map[string]interface{}{"mailbox": "admin.example.com.", "txt": "."},
+			[]string{"admin.example.com.", "."},
+		},
+		"CAA": {
+			map[string]interface{}{"flags": 0, "tag": "issue", "value": "letsencrypt.org"},
+			[]string{"0", "issue", "letsencrypt.org"},
+		},
+		"SSHFP": {
+			map[string]interface{}{"algorithm": 4, "fingerprint_type": 2, "fingerprint": "abcdef0123"},
+			[]string{"4", "2", "abcdef0123"},
+		},
+		"TLSA": {
+			map[string]interface{}{"cert_usage": 3, "selector": 1, "matching_type": 1, "cert_data": "abcdef"},
+			[]string{"3", "1", "1", "abcdef"},
+		},
+		"DS": {
+			map[string]interface{}{"key_tag": 60485, "algorithm": 5, "digest_type": 1, "digest": "2bb183af5f22588179a53b0a98631fad1a292118"},
+			[]string{"60485", "5", "1", "2bb183af5f22588179a53b0a98631fad1a292118"},
+		},
+		"CERT": {
+			map[string]interface{}{"type": "PGP", "key_tag": 0, "algorithm": "0", "certificate": "AAAA"},
+			[]string{"PGP", "0", "0", "AAAA"},
+		},
+		"URLFWD": {
+			map[string]interface{}{"from": "/", "to": "https://example.com", "redirect_type": 0, "path_forwarding": 2, "query_forwarding": 1},
+			[]string{"/", "https://example.com", "0", "2", "1"},
+		},
+	}
+	for typ, c := range cases {
+		f := rdataFormats[typ]
+		if rdata := f.toRdata(c.block); !reflect.DeepEqual(rdata, c.rdata) {
+			t.Fatalf("%s: expected rdata %v, got %v", typ, c.rdata, rdata)
+		}
+		if block := f.fromRdata(c.rdata); !reflect.DeepEqual(block, c.block) {
+			t.Fatalf("%s: expected block %v, got %v", typ, c.block, block)
+		}
+		// The API may hand back the rdata as a single string.
+		if block := f.fromRdata([]string{strings.Join(c.rdata, " ")}); !reflect.DeepEqual(block, c.block) {
+			t.Fatalf("%s: expected block %v from joined rdata, got %v", typ, c.block, block)
+		}
+	}
+}
+
+func TestRdataNAPTRRoundTrip(t *testing.T) {
+	f := rdataFormats["NAPTR"]
+	block := map[string]interface{}{
+		"order":       100,
+		"preference":  10,
+		"flags":       "u",
+		"service":     "E2U+sip",
+		"regexp":      "!^(.*) x$!sip:info@example.com!",
+		"replacement": ".",
+	}
+	rdata := f.toRdata(block)
+	if rdata[4] != "!^(.*) x$!sip:info@example.com!" {
+		t.Fatalf("expected the regexp to be kept whole, got %v", rdata)
+	}
+	if back := f.fromRdata(rdata); !reflect.DeepEqual(back, block) {
+		t.Fatalf("expected block %v, got %v", block, back)
+	}
+
+	empty := map[string]interface{}{
+		"order":       100,
+		"preference":  10,
+		"flags":       "",
+		"service":     "",
+		"regexp":      "",
+		"replacement": "sip.example.com.",
+	}
+	if back := f.fromRdata(f.toRdata(empty)); !reflect.DeepEqual(back, empty) {
+		t.Fatalf("expected block %v, got %v", empty, back)
+	}
+}
+
+func TestRdataValidation(t *testing.T) {
+	s := rdataFormats["SRV"].schema().Elem.(*schema.Resource).Schema
+	if _, es := s["port"].ValidateFunc(70000, "port"); len(es) == 0 {
+		t.Fatalf("expected an error for an out of range port")
+	}
+	if _, es := s["target"].ValidateFunc("not a host", "target"); len(es) == 0 {
+		t.Fatalf("expected an error for a target with spaces")
+	}
+	if _, es := s["target"].ValidateFunc("sip.example.com.", "target"); len(es) != 0 {
+		t.Fatalf("unexpected errors: %v", es)
+	}
+}
+
+func TestRdataFromRdataRejectsMismatches(t *testing.T) {
+	f := rdataFormats["CAA"]
+	if block := f.fromRdata([]string{"0", "issue"}); block != nil {
+		t.Fatalf("expected nil for short rdata, got %v", block)
+	}
+	if block := f.fromRdata([]string{"x", "issue", "letsencrypt.org"}); block != nil {
+		t.Fatalf("expected nil for a non-numeric int field, got %v", block)
+	}
+	block := f.fromRdata([]string{"0", "iodef", "mailto:security", "example.com"})
+	if block["value"] != "mailto:security example.com" {
+		t.Fatalf("expected extra rdata joined into the last field, got %v", block)
+	}
+}
+
+func TestAnswersToHashStructured(t *testing.T) {
+	structured := map[string]interface{}{
+		"answer": "",
+		"caa": []interface{}{
+			map[string]interface{}{"flags": 0, "tag": "issue", "value": "letsencrypt.org"},
+		},
+	}
+	plain := map[string]interface{}{
+		"answer": "0 issue letsencrypt.org",
+	}
+	read := answerToMap("CAA", nsone.Answer{Answer: []string{"0", "issue", "letsencrypt.org"}})
+	if _, ok := read["caa"]; !ok {
+		t.Fatalf("expected caa block to be read back, got %v", read)
+	}
+	h := answersToHash(structured)
+	if answersToHash(plain) != h || answersToHash(read) != h {
+		t.Fatalf("expected structured, plain and read back answers to hash the same")
+	}
+}
+
+func TestValidateStructuredAnswer(t *testing.T) {
+	caa := map[string]interface{}{
+		"caa": []interface{}{
+			map[string]interface{}{"flags": 0, "tag": "issue", "value": "letsencrypt.org"},
+		},
+	}
+	if err := validateStructuredAnswer("CAA", caa); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := validateStructuredAnswer("TLSA", caa); err == nil {
+		t.Fatalf("expected an error for a caa block in a TLSA record")
+	}
+	if err := validateStructuredAnswer("A", map[string]interface{}{"answer": ""}); err == nil {
+		t.Fatalf("expected an error for an empty answer")
+	}
+}
+
+func TestChunkTXT(t *testing.T) {
+	long := strings.Repeat("a", 600)
+	parts := chunkTXT(long)
+	if len(parts) != 3 || len(parts[0]) != 255 || len(parts[1]) != 255 || len(parts[2]) != 90 {
+		t.Fatalf("bad chunks: %d parts", len(parts))
+	}
+	if strings.Join(parts, "") != long {
+		t.Fatalf("chunks do not join back to the value")
+	}
+
+	// A multi-byte character straddling the limit moves to the next chunk.
+	v := strings.Repeat("a", 254) + "é" + "b"
+	parts = chunkTXT(v)
+	if len(parts) != 2 || parts[0] != strings.Repeat("a", 254) || parts[1] != "éb" {
+		t.Fatalf("bad chunks: %q", parts)
+	}
+
+	if parts := chunkTXT("v=spf1 a -all"); len(parts) != 1 {
+		t.Fatalf("short values should not be split: %q", parts)
+	}
+}
+
+func TestTXTAnswerRoundTrip(t *testing.T) {
+	dkim := "v=DKIM1; k=rsa; p=" + strings.Repeat("MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8A", 12)
+	rdata := answerRdata("TXT", map[string]interface{}{"answer": dkim})
+	if len(rdata) != 2 {
+		t.Fatalf("expected a long value to be chunked, got %d parts", len(rdata))
+	}
+	read := answerToMap("TXT", nsone.Answer{Answer: rdata})
+	if read["answer"] != dkim {
+		t.Fatalf("expected %q back, got %q", dkim, read["answer"])
+	}
+	if answersToHash(read) != answersToHash(map[string]interface{}{"answer": dkim}) {
+		t.Fatalf("expected the read back answer to hash the same as the configured one")
+	}
+
+	parts := map[string]interface{}{"answer_parts": []interface{}{"v=spf1 include:a.example.com", " include:b.example.com -all"}}
+	rdata = answerRdata("SPF", parts)
+	if !reflect.DeepEqual(rdata, []string{"v=spf1 include:a.example.com", " include:b.example.com -all"}) {
+		t.Fatalf("expected answer_parts to be sent as they are, got %q", rdata)
+	}
+	read = answerToMap("SPF", nsone.Answer{Answer: rdata})
+	if !reflect.DeepEqual(read["answer_parts"], parts["answer_parts"]) {
+		t.Fatalf("expected answer_parts back, got %v", read["answer_parts"])
+	}
+	if answersToHash(read) != answersToHash(parts) {
+		t.Fatalf("expected the read back answer to hash the same as the configured one")
+	}
+
+	if err := validateStructuredAnswer("A", parts); err == nil {
+		t.Fatalf("expected an error for answer_parts in an A record")
+	}
+	parts["answer"] = "something else"
+	if err := validateStructuredAnswer("TXT", parts); err == nil {
+		t.Fatalf("expected an error for disagreeing answer and answer_parts")
+	}
+}
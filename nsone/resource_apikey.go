@@ -28,6 +28,9 @@ func apikeyResource() *schema.Resource {
 		Read:   ApikeyRead,
 		Update: ApikeyUpdate,
 		Delete: ApikeyDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 	}
 }
 
@@ -131,7 +134,7 @@ func resourceDataToApikey(u *nsone.Apikey, d *schema.ResourceData) error {
 }
 
 func ApikeyCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	mj := nsone.Apikey{}
 	if err := resourceDataToApikey(&mj, d); err != nil {
 		return err
@@ -143,24 +146,24 @@ func ApikeyCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func ApikeyRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	mj, err := client.GetApikey(d.Id())
 	if err != nil {
-		return err
+		return readError(d, "API key", err)
 	}
 	apikeyToResourceData(d, &mj)
 	return nil
 }
 
 func ApikeyDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	err := client.DeleteApikey(d.Id())
 	d.SetId("")
 	return err
 }
 
 func ApikeyUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	mj := nsone.Apikey{
 		Id: d.Id(),
 	}
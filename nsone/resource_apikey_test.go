@@ -0,0 +1,52 @@
+package nsone
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccApikey_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckApikeyDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccApikey_basic,
+			},
+			resource.TestStep{
+				ResourceName:      "nsone_apikey.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckApikeyDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "nsone_apikey" {
+			continue
+		}
+
+		_, err := client.GetApikey(rs.Primary.ID)
+
+		if !isNotFound(err) {
+			return fmt.Errorf("API key still exists")
+		}
+	}
+
+	return nil
+}
+
+const testAccApikey_basic = `
+resource "nsone_apikey" "foobar" {
+	name = "terraform test"
+	dns_view_zones = true
+	monitoring_view_jobs = true
+}`
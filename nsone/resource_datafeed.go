@@ -1,6 +1,9 @@
 package nsone
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	nsone "gopkg.in/sarguru/ns1-go.v18"
 )
@@ -26,6 +29,9 @@ func dataFeedResource() *schema.Resource {
 		Read:   DataFeedRead,
 		Update: DataFeedUpdate,
 		Delete: DataFeedDelete,
+		Importer: &schema.ResourceImporter{
+			State: DataFeedImport,
+		},
 	}
 }
 
@@ -47,7 +53,7 @@ func resourceDataToDataFeed(d *schema.ResourceData) *nsone.DataFeed {
 }
 
 func DataFeedCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	df := resourceDataToDataFeed(d)
 	if err := client.CreateDataFeed(df); err != nil {
 		return err
@@ -57,24 +63,24 @@ func DataFeedCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func DataFeedRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	df, err := client.GetDataFeed(d.Get("source_id").(string), d.Id())
 	if err != nil {
-		return err
+		return readError(d, "data feed", err)
 	}
 	dataFeedToResourceData(d, df)
 	return nil
 }
 
 func DataFeedDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	err := client.DeleteDataFeed(d.Get("source_id").(string), d.Id())
 	d.SetId("")
 	return err
 }
 
 func DataFeedUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	df := resourceDataToDataFeed(d)
 	df.Id = d.Id()
 	if err := client.UpdateDataFeed(df); err != nil {
@@ -83,3 +89,16 @@ func DataFeedUpdate(d *schema.ResourceData, meta interface{}) error {
 	dataFeedToResourceData(d, df)
 	return nil
 }
+
+// DataFeedImport - import data feed from existing NS1 configuration. ID is specified by 'source_id/feed_id'.
+func DataFeedImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid data feed specifier - expecting 'source_id/feed_id', got %q", d.Id())
+	}
+
+	d.Set("source_id", parts[0])
+	d.SetId(parts[1])
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -55,6 +55,31 @@ func TestAccDataFeed_updated(t *testing.T) {
 	})
 }
 
+func TestAccDataFeed_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDataFeedDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccDataFeed_basic,
+			},
+			resource.TestStep{
+				ResourceName:      "nsone_datafeed.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources["nsone_datafeed.foobar"]
+					if !ok {
+						return "", fmt.Errorf("Not found: %s", "nsone_datafeed.foobar")
+					}
+					return fmt.Sprintf("%s/%s", rs.Primary.Attributes["source_id"], rs.Primary.ID), nil
+				},
+			},
+		},
+	})
+}
+
 func testAccCheckDataFeedState(key, value string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources["nsone_datafeed.foobar"]
@@ -93,7 +118,7 @@ func testAccCheckDataFeedExists(n string, dsrc string, dataFeed *nsone.DataFeed)
 			return fmt.Errorf("NoID is set for the datasource")
 		}
 
-		client := testAccProvider.Meta().(*nsone.APIClient)
+		client := testAccProvider.Meta().(*Client)
 
 		foundFeed, err := client.GetDataFeed(ds.Primary.Attributes["id"], rs.Primary.Attributes["id"])
 
@@ -114,7 +139,7 @@ func testAccCheckDataFeedExists(n string, dsrc string, dataFeed *nsone.DataFeed)
 }
 
 func testAccCheckDataFeedDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*nsone.APIClient)
+	client := testAccProvider.Meta().(*Client)
 
 	var dataFeedId string
 	var dataSourceId string
@@ -22,6 +22,9 @@ func dataSourceResource() *schema.Resource {
 		Read:   DataSourceRead,
 		Update: DataSourceUpdate,
 		Delete: DataSourceDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 	}
 }
 
@@ -32,7 +35,7 @@ func dataSourceToResourceData(d *schema.ResourceData, ds *nsone.DataSource) {
 }
 
 func DataSourceCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	ds := nsone.NewDataSource(d.Get("name").(string), d.Get("sourcetype").(string))
 	if err := client.CreateDataSource(ds); err != nil {
 		return err
@@ -42,24 +45,24 @@ func DataSourceCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func DataSourceRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	ds, err := client.GetDataSource(d.Id())
 	if err != nil {
-		return err
+		return readError(d, "data source", err)
 	}
 	dataSourceToResourceData(d, ds)
 	return nil
 }
 
 func DataSourceDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	err := client.DeleteDataSource(d.Id())
 	d.SetId("")
 	return err
 }
 
 func DataSourceUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	ds := nsone.NewDataSource(d.Get("name").(string), d.Get("sourcetype").(string))
 	ds.Id = d.Id()
 	if err := client.UpdateDataSource(ds); err != nil {
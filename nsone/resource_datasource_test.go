@@ -55,6 +55,24 @@ func TestAccDataSource_updated(t *testing.T) {
 	})
 }
 
+func TestAccDataSource_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDataSourceDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccDataSource_basic,
+			},
+			resource.TestStep{
+				ResourceName:      "nsone_datasource.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func testAccCheckDataSourceState(key, value string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources["nsone_datasource.foobar"]
@@ -88,7 +106,7 @@ func testAccCheckDataSourceExists(n string, dataSource *nsone.DataSource) resour
 			return fmt.Errorf("NoID is set")
 		}
 
-		client := testAccProvider.Meta().(*nsone.APIClient)
+		client := testAccProvider.Meta().(*Client)
 
 		foundSource, err := client.GetDataSource(rs.Primary.Attributes["id"])
 
@@ -109,7 +127,7 @@ func testAccCheckDataSourceExists(n string, dataSource *nsone.DataSource) resour
 }
 
 func testAccCheckDataSourceDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*nsone.APIClient)
+	client := testAccProvider.Meta().(*Client)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "nsone_datasource" {
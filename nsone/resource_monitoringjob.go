@@ -104,6 +104,9 @@ func monitoringJobResource() *schema.Resource {
 		Read:   MonitoringJobRead,
 		Update: MonitoringJobUpdate,
 		Delete: MonitoringJobDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 	}
 }
 
@@ -153,6 +156,7 @@ func monitoringJobToResourceData(d *schema.ResourceData, r *nsone.MonitoringJob)
 			m["key"] = r.Key
 			rules[i] = m
 		}
+		d.Set("rules", rules)
 	}
 	return nil
 }
@@ -238,7 +242,7 @@ func resourceDataToMonitoringJob(r *nsone.MonitoringJob, d *schema.ResourceData)
 }
 
 func MonitoringJobCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	mj := nsone.MonitoringJob{}
 	if err := resourceDataToMonitoringJob(&mj, d); err != nil {
 		return err
@@ -250,24 +254,24 @@ func MonitoringJobCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func MonitoringJobRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	mj, err := client.GetMonitoringJob(d.Id())
 	if err != nil {
-		return err
+		return readError(d, "monitoring job", err)
 	}
 	monitoringJobToResourceData(d, &mj)
 	return nil
 }
 
 func MonitoringJobDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	err := client.DeleteMonitoringJob(d.Id())
 	d.SetId("")
 	return err
 }
 
 func MonitoringJobUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	mj := nsone.MonitoringJob{
 		Id: d.Id(),
 	}
@@ -55,6 +55,24 @@ func TestAccMonitoringJob_updated(t *testing.T) {
 	})
 }
 
+func TestAccMonitoringJob_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMonitoringJobDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccMonitoringJob_basic,
+			},
+			resource.TestStep{
+				ResourceName:      "nsone_monitoringjob.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func testAccCheckMonitoringJobState(key, value string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources["nsone_monitoringjob.foobar"]
@@ -88,7 +106,7 @@ func testAccCheckMonitoringJobExists(n string, monitoringJob *nsone.MonitoringJo
 			return fmt.Errorf("NoID is set")
 		}
 
-		client := testAccProvider.Meta().(*nsone.APIClient)
+		client := testAccProvider.Meta().(*Client)
 
 		foundMj, err := client.GetMonitoringJob(rs.Primary.Attributes["id"])
 
@@ -109,7 +127,7 @@ func testAccCheckMonitoringJobExists(n string, monitoringJob *nsone.MonitoringJo
 }
 
 func testAccCheckMonitoringJobDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*nsone.APIClient)
+	client := testAccProvider.Meta().(*Client)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "nsone_monitoringjob" {
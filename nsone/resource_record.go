@@ -2,237 +2,224 @@ package nsone
 
 import (
 	"bytes"
-	"errors"
 	"fmt"
 	"log"
 	"regexp"
-	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/structure"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	nsone "gopkg.in/sarguru/ns1-go.v18"
 )
 
 func recordResource() *schema.Resource {
 	return &schema.Resource{
-		Schema: map[string]*schema.Schema{
-			"zone": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+		Schema:        recordSchema(),
+		SchemaVersion: 5,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    recordResourceV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: recordStateUpgradeV0,
 			},
-			"domain": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+			{
+				Version: 1,
+				Type:    recordResourceV1().CoreConfigSchema().ImpliedType(),
+				Upgrade: recordStateUpgradeV1,
 			},
-			"ttl": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				Computed: true,
+			{
+				Version: 2,
+				Type:    recordResourceV2().CoreConfigSchema().ImpliedType(),
+				Upgrade: recordStateUpgradeV2,
 			},
-			"type": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
-				ValidateFunc: func(v interface{}, k string) (ws []string, es []error) {
-					value := v.(string)
-					if !regexp.MustCompile(`^(A|AAAA|ALIAS|AFSDB|CNAME|DNAME|HINFO|MX|NAPTR|NS|PTR|RP|SPF|SRV|TXT)$`).MatchString(value) {
-						es = append(es, fmt.Errorf(
-							"only A, AAAA, ALIAS, AFSDB, CNAME, DNAME, HINFO, MX, NAPTR, NS, PTR, RP, SPF, SRV, TXT allowed in %q", k))
-					}
-					return
-				},
+			{
+				Version: 3,
+				Type:    recordResourceV3().CoreConfigSchema().ImpliedType(),
+				Upgrade: recordStateUpgradeV3,
 			},
-			"meta": metaSchema(),
-			"link": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+			{
+				Version: 4,
+				Type:    recordResourceV4().CoreConfigSchema().ImpliedType(),
+				Upgrade: recordStateUpgradeV4,
 			},
-			"use_client_subnet": &schema.Schema{
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
+		},
+		CustomizeDiff: RecordCustomizeDiff,
+		Create:        RecordCreate,
+		Read:          RecordRead,
+		Update:        RecordUpdate,
+		Delete:        RecordDelete,
+		Importer: &schema.ResourceImporter{
+			State: RecordImport,
+		},
+	}
+}
+
+// recordSchema is the schema of nsone_record, shared with the schemas it is
+// upgraded from, see recordResourceV4.
+func recordSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"zone": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+		"domain": &schema.Schema{
+			Type:             schema.TypeString,
+			Required:         true,
+			ForceNew:         true,
+			DiffSuppressFunc: suppressEquivalentDomain,
+		},
+		"ttl": &schema.Schema{
+			Type:     schema.TypeInt,
+			Optional: true,
+			Computed: true,
+		},
+		"type": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+			ValidateFunc: func(v interface{}, k string) (ws []string, es []error) {
+				value := v.(string)
+				if !regexp.MustCompile(`^(A|AAAA|ALIAS|AFSDB|CAA|CERT|CNAME|DNAME|DS|HINFO|MX|NAPTR|NS|PTR|RP|SPF|SRV|SSHFP|TLSA|TXT|URLFWD)$`).MatchString(value) {
+					es = append(es, fmt.Errorf(
+						"only A, AAAA, ALIAS, AFSDB, CAA, CERT, CNAME, DNAME, DS, HINFO, MX, NAPTR, NS, PTR, RP, SPF, SRV, SSHFP, TLSA, TXT, URLFWD allowed in %q", k))
+				}
+				return
 			},
-			"answers": &schema.Schema{
-				Type:     schema.TypeSet,
-				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"answer": &schema.Schema{
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"region": &schema.Schema{
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"meta": &schema.Schema{
-							Type:     schema.TypeSet,
-							Optional: true,
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"field": &schema.Schema{
-										Type:     schema.TypeString,
-										Optional: true,
-									},
-									"feed": &schema.Schema{
-										Type:     schema.TypeString,
-										Optional: true,
-										//ConflictsWith: []string{"value"},
-									},
-									"value": &schema.Schema{
-										Type:     schema.TypeString,
-										Optional: true,
-										//ConflictsWith: []string{"feed"},
-									},
-								},
-							},
-							Set: metaToHash,
-						},
-					},
-				},
-				Set: answersToHash,
+		},
+		"meta": metaBlockSchema(),
+		"link": &schema.Schema{
+			Type:     schema.TypeString,
+			Optional: true,
+			ForceNew: true,
+		},
+		"use_client_subnet": &schema.Schema{
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+		"answers": &schema.Schema{
+			Type:     schema.TypeSet,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: answerSchema(),
+			},
+			Set:           answersToHash,
+			ConflictsWith: []string{"answer"},
+		},
+		"answer": &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: answerSchema(),
 			},
-			"regions": &schema.Schema{
-				Type:     schema.TypeSet,
-				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"name": &schema.Schema{
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"georegion": &schema.Schema{
-							Type:     schema.TypeString,
-							Optional: true,
-							ValidateFunc: func(v interface{}, k string) (ws []string, es []error) {
-								value := v.(string)
-								if !regexp.MustCompile(`^(US-WEST|US-EAST|US-CENTRAL|EUROPE|AFRICA|ASIAPAC|SOUTH-AMERICA)$`).MatchString(value) {
-									es = append(es, fmt.Errorf(
-										"only US-WEST, US-EAST, US-CENTRAL, EUROPE, AFRICA, ASIAPAC, SOUTH-AMERICA allowed in %q", k))
-								}
-								return
-							},
-						},
-						"country": &schema.Schema{
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"us_state": &schema.Schema{
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"longitude": &schema.Schema{
-							Type:     schema.TypeFloat,
-							Optional: true,
-						},
-						"latitude": &schema.Schema{
-							Type:     schema.TypeFloat,
-							Optional: true,
-						},
-						"up": &schema.Schema{
-							Type:     schema.TypeBool,
-							Optional: true,
-						},
+			ConflictsWith: []string{"answers"},
+		},
+		"regions": &schema.Schema{
+			Type:     schema.TypeSet,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": &schema.Schema{
+						Type:     schema.TypeString,
+						Required: true,
 					},
+					"meta": metaBlockSchema(),
 				},
-				Set: regionsToHash,
 			},
-			"filters": &schema.Schema{
-				Type:     schema.TypeList,
-				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"filter": &schema.Schema{
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"disabled": &schema.Schema{
-							Type:     schema.TypeBool,
-							Optional: true,
-						},
-						"config": &schema.Schema{
-							Type:     schema.TypeMap,
-							Optional: true,
-						},
+			Set: regionsToHash,
+		},
+		"filters": &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"filter": &schema.Schema{
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validateFilterName,
+					},
+					"disabled": &schema.Schema{
+						Type:     schema.TypeBool,
+						Optional: true,
+					},
+					"config": filterConfigSchema(),
+					"config_json": &schema.Schema{
+						Type:             schema.TypeString,
+						Optional:         true,
+						ValidateFunc:     validation.StringIsJSON,
+						DiffSuppressFunc: structure.SuppressJsonDiff,
 					},
 				},
 			},
 		},
-		Create: RecordCreate,
-		Read:   RecordRead,
-		Update: RecordUpdate,
-		Delete: RecordDelete,
-		Importer: &schema.ResourceImporter{
-			State: RecordImport,
+	}
+}
+
+// answerSchema is the schema of an answer. Besides the answer string, each
+// record type with a structured rdata format has a block of its own, which
+// can be given instead; both are filled in when reading the record.
+func answerSchema() map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"answer": &schema.Schema{
+			Type:             schema.TypeString,
+			Optional:         true,
+			Computed:         true,
+			DiffSuppressFunc: suppressEquivalentAnswer,
+		},
+		"answer_parts": &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			Computed: true,
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validateCharacterString,
+			},
+		},
+		"region": &schema.Schema{
+			Type:     schema.TypeString,
+			Optional: true,
 		},
+		"meta": metaBlockSchema(),
 	}
+	for _, f := range rdataFormats {
+		s[f.block] = f.schema()
+	}
+	return s
 }
 
 func regionsToHash(v interface{}) int {
 	var buf bytes.Buffer
 	r := v.(map[string]interface{})
 	buf.WriteString(fmt.Sprintf("%s-", r["name"].(string)))
-	buf.WriteString(fmt.Sprintf("%s-", r["georegion"].(string)))
-	buf.WriteString(fmt.Sprintf("%s-", r["country"].(string)))
-	buf.WriteString(fmt.Sprintf("%s-", r["us_state"].(string)))
-	buf.WriteString(fmt.Sprintf("%f-", r["latitude"].(float64)))
-	buf.WriteString(fmt.Sprintf("%f-", r["longitude"].(float64)))
-	buf.WriteString(fmt.Sprintf("%t-", r["up"].(bool)))
+	if meta := metaHashKey(r["meta"]); meta != "" {
+		buf.WriteString(fmt.Sprintf("%s-", meta))
+	}
 	return hashcode.String(buf.String())
 }
 
 func answersToHash(v interface{}) int {
 	var buf bytes.Buffer
 	a := v.(map[string]interface{})
-	buf.WriteString(fmt.Sprintf("%s-", a["answer"].(string)))
+	// Hash the rdata rather than the answer string, so an answer given as a
+	// structured block or answer_parts matches the same answer read back
+	// from the API.
+	buf.WriteString(fmt.Sprintf("%s-", answerKey(a)))
 	if a["region"] != nil && a["region"].(string) != "" {
 		buf.WriteString(fmt.Sprintf("%s-", a["region"].(string)))
 	}
-	metas := make([]int, 0)
-	switch t := a["meta"].(type) {
-	default:
-		panic(fmt.Sprintf("unexpected type %T", t))
-	case nil:
-	case *schema.Set:
-		for _, meta := range t.List() {
-			metas = append(metas, metaToHash(meta))
-		}
-	case []map[string]interface{}:
-		for _, meta := range t {
-			metas = append(metas, metaToHash(meta))
-		}
-	}
-	sort.Ints(metas)
-	for _, metahash := range metas {
-		buf.WriteString(fmt.Sprintf("%d-", metahash))
+	if meta := metaHashKey(a["meta"]); meta != "" {
+		buf.WriteString(fmt.Sprintf("%s-", meta))
 	}
 	hash := hashcode.String(buf.String())
 	log.Printf("Generated answersToHash %d from %+v", hash, a)
 	return hash
 }
 
-func metaToHash(v interface{}) int {
-	var buf bytes.Buffer
-	s := v.(map[string]interface{})
-	buf.WriteString(fmt.Sprintf("%s-", s["field"].(string)))
-	if v, ok := s["feed"]; ok && v.(string) != "" {
-		buf.WriteString(fmt.Sprintf("feed%s-", v.(string)))
-	}
-	if v, ok := s["value"]; ok && v.(string) != "" {
-		buf.WriteString(fmt.Sprintf("value%s-", v.(string)))
-	}
-
-	hash := hashcode.String(buf.String())
-	log.Printf("Generated metaToHash %d from %+v", hash, s)
-	return hash
-}
-
-func recordToResourceData(d *schema.ResourceData, r *nsone.Record) error {
+func recordToResourceData(d *schema.ResourceData, r *Record) error {
 	d.SetId(r.Id)
 	d.Set("domain", r.Domain)
 	d.Set("zone", r.Zone)
@@ -243,11 +230,9 @@ func recordToResourceData(d *schema.ResourceData, r *nsone.Record) error {
 		d.Set("link", r.Link)
 	}
 	if len(r.Meta) > 0 {
-		meta := make(map[string]interface{})
-		for name, value := range r.Meta {
-			meta[name] = value
-		}
-		d.Set("meta", meta)
+		d.Set("meta", []interface{}{metaAPIToBlock(r.Meta)})
+	} else {
+		d.Set("meta", nil)
 	}
 	if len(r.Filters) > 0 {
 		filters := make([]map[string]interface{}, len(r.Filters))
@@ -257,20 +242,38 @@ func recordToResourceData(d *schema.ResourceData, r *nsone.Record) error {
 			if f.Disabled {
 				m["disabled"] = true
 			}
-			if f.Config != nil {
-				m["config"] = f.Config
+			prior, _ := d.Get(fmt.Sprintf("filters.%d.config_json", i)).(string)
+			block, configJSON := filterConfigFromAPI(f.Filter, f.Config, prior != "")
+			if block != nil {
+				m["config"] = []interface{}{block}
+			}
+			if configJSON != "" {
+				m["config_json"] = configJSON
 			}
 			filters[i] = m
 		}
 		d.Set("filters", filters)
 	}
-	if len(r.Answers) > 0 {
+	if _, ordered := d.GetOk("answer"); ordered {
+		// Answers given as an ordered list are read back in the order the
+		// API holds them, so a change of order shows as a diff.
+		ans := make([]interface{}, len(r.Answers))
+		for i, answer := range r.Answers {
+			ans[i] = answerToMap(r.Type, answer)
+		}
+		log.Printf("Setting answer %+v", ans)
+		err := d.Set("answer", ans)
+		if err != nil {
+			return fmt.Errorf("[DEBUG] Error setting answer for: %s, error: %#v", r.Domain, err)
+		}
+	} else if len(r.Answers) > 0 {
 		ans := &schema.Set{
 			F: answersToHash,
 		}
 		log.Printf("Got back from nsone answers: %+v", r.Answers)
+		prior := d.Get("answers").(*schema.Set).List()
 		for _, answer := range r.Answers {
-			ans.Add(answerToMap(answer))
+			ans.Add(keepAnswerSpelling(r.Type, answerToMap(r.Type, answer), prior))
 		}
 		log.Printf("Setting answers %+v", ans)
 		err := d.Set("answers", ans)
@@ -283,25 +286,8 @@ func recordToResourceData(d *schema.ResourceData, r *nsone.Record) error {
 		for region_name, region := range r.Regions {
 			new_region := make(map[string]interface{})
 			new_region["name"] = region_name
-			if len(region.Meta.GeoRegion) > 0 {
-				new_region["georegion"] = region.Meta.GeoRegion[0]
-			}
-			if len(region.Meta.Country) > 0 {
-				new_region["country"] = region.Meta.Country[0]
-			}
-			if len(region.Meta.USState) > 0 {
-				new_region["us_state"] = region.Meta.USState[0]
-			}
-			if region.Meta.Latitude > 0 {
-				new_region["latitude"] = strconv.FormatFloat(region.Meta.Latitude, 'f', 1, 64)
-			}
-			if region.Meta.Longitude > 0 {
-				new_region["longitude"] = strconv.FormatFloat(region.Meta.Longitude, 'f', 1, 64)
-			}
-			if region.Meta.Up {
-				new_region["up"] = region.Meta.Up
-			} else {
-				new_region["up"] = false
+			if len(region.Meta) > 0 {
+				new_region["meta"] = []interface{}{metaAPIToBlock(region.Meta)}
 			}
 			regions = append(regions, new_region)
 		}
@@ -314,93 +300,73 @@ func recordToResourceData(d *schema.ResourceData, r *nsone.Record) error {
 	return nil
 }
 
-func answerToMap(a nsone.Answer) map[string]interface{} {
+func answerToMap(t string, a nsone.Answer) map[string]interface{} {
 	m := make(map[string]interface{})
-	m["answer"] = strings.Join(a.Answer, " ")
+	rdata := canonicalRdata(t, a.Answer)
+	if isTXT(t) {
+		parts := make([]interface{}, len(rdata))
+		for i, p := range rdata {
+			parts[i] = p
+		}
+		m["answer"] = strings.Join(rdata, "")
+		m["answer_parts"] = parts
+	} else {
+		m["answer"] = strings.Join(rdata, " ")
+	}
+	if f, ok := rdataFormats[t]; ok {
+		if block := f.fromRdata(rdata); block != nil {
+			m[f.block] = []interface{}{block}
+		}
+	}
 	if a.Region != "" {
 		m["region"] = a.Region
 	}
-	if a.Meta != nil {
-		metas := &schema.Set{
-			F: metaToHash,
-		}
-		for k, v := range a.Meta {
-			meta := make(map[string]interface{})
-			meta["field"] = k
-			switch t := v.(type) {
-			case map[string]interface{}:
-				meta["feed"] = t["feed"].(string)
-			case string:
-				meta["value"] = t
-			case []interface{}:
-				var val_array []string
-				for _, pref := range t {
-					val_array = append(val_array, pref.(string))
-				}
-				sort.Strings(val_array)
-				string_val := strings.Join(val_array, ",")
-				meta["value"] = string_val
-			case bool:
-				int_val := btoi(t)
-				meta["value"] = strconv.Itoa(int_val)
-			case float64:
-				int_val := int(t)
-				meta["value"] = strconv.Itoa(int_val)
-			}
-			metas.Add(meta)
-		}
-		m["meta"] = metas
+	if len(a.Meta) > 0 {
+		m["meta"] = []interface{}{metaAPIToBlock(a.Meta)}
 	}
 	return m
 }
 
-func btoi(b bool) int {
-	if b {
-		return 1
-	}
-	return 0
-}
-
-func resourceDataToRecord(r *nsone.Record, d *schema.ResourceData) error {
+func resourceDataToRecord(r *Record, d *schema.ResourceData) error {
 	r.Id = d.Id()
-	if answers := d.Get("answers").(*schema.Set); answers.Len() > 0 {
-		al := make([]nsone.Answer, answers.Len())
-		for i, answer_raw := range answers.List() {
+	if link := d.Get("link").(string); link != "" {
+		if err := checkRecordLink(link, d.Get); err != nil {
+			return err
+		}
+	}
+	if block := metaBlock(d.Get("meta")); block != nil {
+		meta, err := metaBlockToAPI(block)
+		if err != nil {
+			return err
+		}
+		r.Meta = meta
+	}
+	if answers := recordAnswers(d); len(answers) > 0 {
+		al := make([]nsone.Answer, len(answers))
+		for i, answer_raw := range answers {
 			answer := answer_raw.(map[string]interface{})
 			a := nsone.NewAnswer()
-			v := answer["answer"].(string)
-			if d.Get("type") != "TXT" {
-				a.Answer = strings.Split(v, " ")
-			} else {
-				a.Answer = []string{v}
+			t := d.Get("type").(string)
+			if err := validateStructuredAnswer(t, answer); err != nil {
+				return err
 			}
+			if err := validateAnswerAddress(t, answer); err != nil {
+				return err
+			}
+			a.Answer = canonicalRdata(t, answerRdata(t, answer))
 			if v, ok := answer["region"]; ok {
 				a.Region = v.(string)
 			}
-			if metas := answer["meta"].(*schema.Set); metas.Len() > 0 {
-				for _, meta_raw := range metas.List() {
-					meta := meta_raw.(map[string]interface{})
-					key := meta["field"].(string)
-					if value, ok := meta["feed"]; ok && value.(string) != "" {
-						a.Meta[key] = nsone.NewMetaFeed(value.(string))
-					}
-					if value, ok := meta["value"]; ok && value.(string) != "" {
-						meta_array := strings.Split(value.(string), ",")
-						if len(meta_array) > 1 {
-							sort.Strings(meta_array)
-							a.Meta[key] = meta_array
-						} else {
-							a.Meta[key] = value.(string)
-						}
-					}
+			if block := metaBlock(answer["meta"]); block != nil {
+				meta, err := metaBlockToAPI(block)
+				if err != nil {
+					return err
 				}
+				a.Meta = meta
 			}
 			al[i] = a
 		}
 		r.Answers = al
-		if _, ok := d.GetOk("link"); ok {
-			return errors.New("Cannot have both link and answers in a record")
-		}
 	}
 	if v, ok := d.GetOk("ttl"); ok {
 		r.Ttl = v.(int)
@@ -417,57 +383,35 @@ func resourceDataToRecord(r *nsone.Record, d *schema.ResourceData) error {
 		f := make([]nsone.Filter, len(rawFilters))
 		for i, filter_raw := range rawFilters {
 			fi := filter_raw.(map[string]interface{})
-			config := make(map[string]interface{})
 			filter := nsone.Filter{
 				Filter: fi["filter"].(string),
-				Config: config,
 			}
 			if disabled, ok := fi["disabled"]; ok {
 				filter.Disabled = disabled.(bool)
 			}
-			if raw_config, ok := fi["config"]; ok {
-				for k, v := range raw_config.(map[string]interface{}) {
-					if v.(string) == "true" || v.(string) == "false" {
-						filter.Config[k], _ = strconv.ParseBool(v.(string))
-					} else {
-						if i, err := strconv.Atoi(v.(string)); err == nil {
-							filter.Config[k] = i
-						} else {
-							filter.Config[k] = v
-						}
-					}
-				}
+			config, err := filterConfig(fi)
+			if err != nil {
+				return err
 			}
+			filter.Config = config
 			f[i] = filter
 		}
 		r.Filters = f
 	}
 	if regions := d.Get("regions").(*schema.Set); regions.Len() > 0 {
-		rm := make(map[string]nsone.Region)
+		rm := make(map[string]Region)
 		for _, region_raw := range regions.List() {
 			region := region_raw.(map[string]interface{})
-			nsone_r := nsone.Region{
-				Meta: nsone.RegionMeta{},
-			}
-			if g := region["georegion"].(string); g != "" {
-				nsone_r.Meta.GeoRegion = []string{g}
-			}
-			if g := region["country"].(string); g != "" {
-				nsone_r.Meta.Country = []string{g}
-			}
-			if g := region["us_state"].(string); g != "" {
-				nsone_r.Meta.USState = []string{g}
-			}
-			if g := region["latitude"].(float64); g != 0 {
-				nsone_r.Meta.Latitude = g
+			nsone_r := Region{
+				Meta: make(map[string]interface{}),
 			}
-			if g := region["longitude"].(float64); g != 0 {
-				nsone_r.Meta.Longitude = g
-			}
-			if g := region["up"].(bool); g {
-				nsone_r.Meta.Up = g
+			if block := metaBlock(region["meta"]); block != nil {
+				meta, err := metaBlockToAPI(block)
+				if err != nil {
+					return err
+				}
+				nsone_r.Meta = meta
 			}
-
 			rm[region["name"].(string)] = nsone_r
 		}
 		r.Regions = rm
@@ -485,9 +429,252 @@ func setToMapByKey(s *schema.Set, key string) map[string]interface{} {
 	return result
 }
 
+// recordAnswers returns the answers of the record d describes, in the order
+// they are declared if they are given as an ordered answer list.
+func recordAnswers(d *schema.ResourceData) []interface{} {
+	if answers, ok := d.GetOk("answer"); ok {
+		l := answers.([]interface{})
+		for i, raw := range l {
+			l[i] = plannedAnswer(d.HasChange, fmt.Sprintf("answer.%d", i), raw.(map[string]interface{}))
+		}
+		return l
+	}
+	return d.Get("answers").(*schema.Set).List()
+}
+
+// RecordCustomizeDiff checks the parts of a record's configuration that
+// depend on each other at plan time, rather than leaving them to fail on
+// apply. Parts that are not known until apply are not checked.
+func RecordCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if err := validateRecordDomain(d); err != nil {
+		return err
+	}
+	if err := validateRecordLink(d); err != nil {
+		return err
+	}
+	if err := validateRecordAnswers(d); err != nil {
+		return err
+	}
+	if err := validateRecordRegions(d); err != nil {
+		return err
+	}
+	return validateRecordFilters(d)
+}
+
+// validateRecordLink checks that a linked record has none of the things
+// which come from the record it is linked to.
+func validateRecordLink(d *schema.ResourceDiff) error {
+	link, _ := d.Get("link").(string)
+	if link == "" || !d.NewValueKnown("link") {
+		return nil
+	}
+	return checkRecordLink(link, d.Get)
+}
+
+// checkRecordLink checks that a record linked to link has none of the
+// things which come from the record it is linked to, reading them with get.
+// It is made at plan time, and again when the record is sent for a link
+// that was not known until apply.
+func checkRecordLink(link string, get func(string) interface{}) error {
+	for _, k := range []string{"answers", "regions"} {
+		if get(k).(*schema.Set).Len() > 0 {
+			return fmt.Errorf("a record linked to %s cannot have %s of its own", link, k)
+		}
+	}
+	for _, k := range []string{"answer", "filters"} {
+		if len(get(k).([]interface{})) > 0 {
+			return fmt.Errorf("a record linked to %s cannot have %s of its own", link, k)
+		}
+	}
+	if metaBlock(get("meta")) != nil {
+		return fmt.Errorf("a record linked to %s cannot have meta of its own", link)
+	}
+	return nil
+}
+
+// validateRecordAnswers checks each answer's form against the record's type,
+// its meta, and that the region it is in is one of the record's regions.
+func validateRecordAnswers(d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("answers") || !d.NewValueKnown("answer") || !d.NewValueKnown("type") {
+		return nil
+	}
+	t := d.Get("type").(string)
+	regionsKnown := d.NewValueKnown("regions")
+	regions := make(map[string]bool)
+	for _, raw := range d.Get("regions").(*schema.Set).List() {
+		regions[raw.(map[string]interface{})["name"].(string)] = true
+	}
+	// The elements of answers have no key to read them by once planned, so
+	// they are keyed by "" and checked by value alone.
+	answers := diffSetElements(d, "answers")
+	keys := make([]string, len(answers))
+	for i, raw := range d.Get("answer").([]interface{}) {
+		key := fmt.Sprintf("answer.%d", i)
+		answers = append(answers, plannedAnswer(d.HasChange, key, raw.(map[string]interface{})))
+		keys = append(keys, key)
+	}
+	// Each check is skipped for an answer with a field it reads not known
+	// until apply.
+	for i, answer := range answers {
+		known := make(map[string]bool)
+		formKnown := true
+		for k, v := range answer {
+			known[k] = diffValueKnown(d, joinDiffKey(keys[i], k), v)
+			if !known[k] && k != "region" && k != "meta" {
+				formKnown = false
+			}
+		}
+		if formKnown {
+			if err := validateStructuredAnswer(t, answer); err != nil {
+				return fmt.Errorf("answer %q: %s", answerKey(answer), err)
+			}
+		}
+		if known["answer"] {
+			if err := validateAnswerAddress(t, answer); err != nil {
+				return err
+			}
+		}
+		if block := metaBlock(answer["meta"]); block != nil && known["meta"] {
+			if _, err := metaBlockToAPI(block); err != nil {
+				return fmt.Errorf("answer %q: meta: %s", answerKey(answer), err)
+			}
+		}
+	}
+	if !regionsKnown {
+		return nil
+	}
+	for _, raw := range d.Get("answers").(*schema.Set).List() {
+		if err := checkAnswerRegion(d, "", raw, regions); err != nil {
+			return err
+		}
+	}
+	for i, raw := range d.Get("answer").([]interface{}) {
+		if err := checkAnswerRegion(d, fmt.Sprintf("answer.%d", i), raw, regions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkAnswerRegion checks that the answer planned at key is in one of
+// regions, unless its region is not known until apply.
+func checkAnswerRegion(d *schema.ResourceDiff, key string, raw interface{}, regions map[string]bool) error {
+	region, _ := raw.(map[string]interface{})["region"].(string)
+	if region == "" || regions[region] || !diffValueKnown(d, joinDiffKey(key, "region"), region) {
+		return nil
+	}
+	return fmt.Errorf("an answer is in region %q, which is not one of the record's regions", region)
+}
+
+// validateRecordRegions checks the meta of the record and of its regions.
+func validateRecordRegions(d *schema.ResourceDiff) error {
+	if block := metaBlock(d.Get("meta")); block != nil && d.NewValueKnown("meta") {
+		if _, err := metaBlockToAPI(block); err != nil {
+			return fmt.Errorf("meta: %s", err)
+		}
+	}
+	if !d.NewValueKnown("regions") {
+		return nil
+	}
+	for _, region := range diffSetElements(d, "regions") {
+		if block := metaBlock(region["meta"]); block != nil {
+			if _, err := metaBlockToAPI(block); err != nil {
+				return fmt.Errorf("region %q: meta: %s", region["name"], err)
+			}
+		}
+	}
+	return nil
+}
+
+// diffSetElements returns the elements of the set at key as planned.
+// ResourceDiff loses the blocks nested in the elements of a set when it reads
+// the whole set, so the elements in the diff are read one at a time by their
+// hash code instead. These include elements being removed, which were checked
+// when they were added; if the set is unchanged it is read from the state,
+// where the whole set reads correctly.
+func diffSetElements(d *schema.ResourceDiff, key string) []map[string]interface{} {
+	prefix := key + "."
+	seen := make(map[string]bool)
+	var elements []map[string]interface{}
+	for _, k := range d.GetChangedKeysPrefix(prefix) {
+		code := strings.SplitN(strings.TrimPrefix(k, prefix), ".", 2)[0]
+		if code == "#" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		if e, ok := d.Get(prefix + code).(map[string]interface{}); ok {
+			elements = append(elements, e)
+		}
+	}
+	if len(seen) > 0 {
+		return elements
+	}
+	for _, raw := range d.Get(key).(*schema.Set).List() {
+		elements = append(elements, raw.(map[string]interface{}))
+	}
+	return elements
+}
+
+// unknownValue is what the SDK plans for a value not known until apply
+// (hcl2shim.UnknownVariableValue) in the elements of a set.
+const unknownValue = "74D93920-ED26-11E3-AC10-0800200C9A66"
+
+// diffValueKnown reports whether v, planned at key, is known along with
+// everything nested in it. In the elements of a list an unknown value reads
+// as its zero value, so each leaf is checked by its key; the elements of a
+// set are read with an empty key and checked for unknownValue alone.
+func diffValueKnown(d *schema.ResourceDiff, key string, v interface{}) bool {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			if !diffValueKnown(d, joinDiffKey(key, k), e) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		for i, e := range v {
+			if !diffValueKnown(d, joinDiffKey(key, strconv.Itoa(i)), e) {
+				return false
+			}
+		}
+		return true
+	case string:
+		if v == unknownValue {
+			return false
+		}
+	}
+	return key == "" || d.NewValueKnown(key)
+}
+
+// joinDiffKey returns the key of field within the value at key, or "" for
+// a value read without a key.
+func joinDiffKey(key, field string) string {
+	if key == "" {
+		return ""
+	}
+	return key + "." + field
+}
+
+// validateRecordFilters checks the config of each filter against the
+// filter's type.
+func validateRecordFilters(d *schema.ResourceDiff) error {
+	filters, _ := d.Get("filters").([]interface{})
+	for i, raw := range filters {
+		fi, ok := raw.(map[string]interface{})
+		if !ok || !diffValueKnown(d, fmt.Sprintf("filters.%d", i), fi) {
+			continue
+		}
+		if _, err := filterConfig(fi); err != nil {
+			return fmt.Errorf("filters.%d: %s", i, err)
+		}
+	}
+	return nil
+}
+
 func RecordCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
-	r := nsone.NewRecord(d.Get("zone").(string), d.Get("domain").(string), d.Get("type").(string))
+	client := meta.(*Client)
+	r := NewRecord(d.Get("zone").(string), recordDomain(d), d.Get("type").(string))
 	if err := resourceDataToRecord(r, d); err != nil {
 		return err
 	}
@@ -498,25 +685,25 @@ func RecordCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func RecordRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
-	r, err := client.GetRecord(d.Get("zone").(string), d.Get("domain").(string), d.Get("type").(string))
+	client := meta.(*Client)
+	r, err := client.GetRecord(d.Get("zone").(string), recordDomain(d), d.Get("type").(string))
 	if err != nil {
-		return err
+		return readError(d, "record", err)
 	}
 	recordToResourceData(d, r)
 	return nil
 }
 
 func RecordDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
-	err := client.DeleteRecord(d.Get("zone").(string), d.Get("domain").(string), d.Get("type").(string))
+	client := meta.(*Client)
+	err := client.DeleteRecord(d.Get("zone").(string), recordDomain(d), d.Get("type").(string))
 	d.SetId("")
 	return err
 }
 
 func RecordUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
-	r := nsone.NewRecord(d.Get("zone").(string), d.Get("domain").(string), d.Get("type").(string))
+	client := meta.(*Client)
+	r := NewRecord(d.Get("zone").(string), recordDomain(d), d.Get("type").(string))
 	if err := resourceDataToRecord(r, d); err != nil {
 		return err
 	}
@@ -0,0 +1,528 @@
+package nsone
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	nsone "gopkg.in/sarguru/ns1-go.v18"
+)
+
+// testRecordDiff plans the creation of a record with the given
+// configuration, running its CustomizeDiff.
+func testRecordDiff(t *testing.T, raw map[string]interface{}) (*terraform.InstanceDiff, error) {
+	t.Helper()
+	return recordResource().Diff(nil, terraform.NewResourceConfigRaw(raw), nil)
+}
+
+func testRecordConfig(extra map[string]interface{}) map[string]interface{} {
+	raw := map[string]interface{}{
+		"zone":   "terraform.io",
+		"domain": "test.terraform.io",
+		"type":   "A",
+	}
+	for k, v := range extra {
+		raw[k] = v
+	}
+	return raw
+}
+
+func TestRecordDiffFilters(t *testing.T) {
+	_, err := testRecordDiff(t, testRecordConfig(map[string]interface{}{
+		"filters": []interface{}{
+			map[string]interface{}{"filter": "up"},
+			map[string]interface{}{"filter": "select_first_n", "config": []interface{}{map[string]interface{}{"n": 1}}},
+			map[string]interface{}{"filter": "pulsar_sort", "config_json": `{"jobs": ["abc"]}`},
+			map[string]interface{}{"filter": "pulsar_stabilize", "config_json": unknownValue},
+		},
+	}))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for _, c := range []struct {
+		filter map[string]interface{}
+		err    string
+	}{
+		{
+			map[string]interface{}{"filter": "select_first_n", "config": []interface{}{map[string]interface{}{"remove_no_location": true}}},
+			"filters.0: filter select_first_n does not take config key remove_no_location",
+		},
+		{
+			map[string]interface{}{"filter": "pulsar_sort", "config": []interface{}{map[string]interface{}{"n": 1}}},
+			"give it as config_json",
+		},
+		{
+			map[string]interface{}{
+				"filter":      "select_first_n",
+				"config":      []interface{}{map[string]interface{}{"n": 1}},
+				"config_json": `{"N": 1}`,
+			},
+			"only one of config and config_json",
+		},
+	} {
+		_, err = testRecordDiff(t, testRecordConfig(map[string]interface{}{
+			"filters": []interface{}{c.filter},
+		}))
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Fatalf("expected an error containing %q, got %v", c.err, err)
+		}
+	}
+
+	for _, config := range []map[string]interface{}{
+		{"n": "one"},
+		{"metric": "cpu"},
+	} {
+		_, es := recordResource().Validate(terraform.NewResourceConfigRaw(testRecordConfig(map[string]interface{}{
+			"filters": []interface{}{
+				map[string]interface{}{"filter": "select_first_n", "config": []interface{}{config}},
+			},
+		})))
+		if len(es) == 0 {
+			t.Fatalf("expected an error for config %v", config)
+		}
+	}
+
+	_, es := recordResource().Validate(terraform.NewResourceConfigRaw(testRecordConfig(map[string]interface{}{
+		"filters": []interface{}{
+			map[string]interface{}{"filter": "geotarget_contry"},
+		},
+	})))
+	if len(es) == 0 {
+		t.Fatalf("expected an error for a misspelled filter")
+	}
+}
+
+// TestRecordFiltersRead sends typed filter config and reads it back as the
+// API returns it, which must plan no change.
+func TestRecordFiltersRead(t *testing.T) {
+	config := testRecordConfig(map[string]interface{}{
+		"ttl": 3600,
+		"filters": []interface{}{
+			map[string]interface{}{"filter": "select_first_n", "config": []interface{}{map[string]interface{}{"n": 2}}},
+			map[string]interface{}{"filter": "geofence_country", "config": []interface{}{map[string]interface{}{"remove_no_location": true}}},
+			map[string]interface{}{"filter": "pulsar_sort", "config_json": `{"jobs": ["abc"]}`},
+		},
+	})
+	d := schema.TestResourceDataRaw(t, recordResource().Schema, config)
+	r := NewRecord("terraform.io", "test.terraform.io", "A")
+	if err := resourceDataToRecord(r, d); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if n := r.Filters[0].Config["N"]; n != 2 {
+		t.Fatalf("expected N to be sent as 2, got %#v", n)
+	}
+
+	// The API returns numbers as float64.
+	r.Id = "test"
+	r.UseClientSubnet = false
+	r.Filters[0].Config["N"] = float64(2)
+	if err := recordToResourceData(d, r); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	diff, err := recordResource().Diff(d.State(), terraform.NewResourceConfigRaw(config), nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diff != nil && !diff.Empty() {
+		t.Fatalf("expected no diff, got %#v", diff.Attributes)
+	}
+}
+
+func TestRecordDiffConflicts(t *testing.T) {
+	answer := func(extra map[string]interface{}) []interface{} {
+		a := map[string]interface{}{"answer": "1.2.3.4"}
+		for k, v := range extra {
+			a[k] = v
+		}
+		return []interface{}{a}
+	}
+	cases := []struct {
+		name   string
+		config map[string]interface{}
+		err    string
+	}{
+		{
+			"plain answers",
+			map[string]interface{}{"answers": answer(nil)},
+			"",
+		},
+		{
+			"link with answers",
+			map[string]interface{}{"link": "other.terraform.io", "answers": answer(nil)},
+			"cannot have answers",
+		},
+		{
+			"link with filters",
+			map[string]interface{}{"link": "other.terraform.io", "filters": []interface{}{map[string]interface{}{"filter": "up"}}},
+			"cannot have filters",
+		},
+		{
+			"answer in an undefined region",
+			map[string]interface{}{"answers": answer(map[string]interface{}{"region": "cal"})},
+			`region "cal"`,
+		},
+		{
+			"answer in a defined region",
+			map[string]interface{}{
+				"answers": answer(map[string]interface{}{"region": "cal"}),
+				"regions": []interface{}{map[string]interface{}{"name": "cal"}},
+			},
+			"",
+		},
+		{
+			"answer in a region not known until apply",
+			map[string]interface{}{
+				"answers": answer(map[string]interface{}{"region": unknownValue}),
+				"regions": []interface{}{map[string]interface{}{"name": "cal"}},
+			},
+			"",
+		},
+		{
+			"ordered answer in a region not known until apply",
+			map[string]interface{}{
+				"answer":  answer(map[string]interface{}{"region": unknownValue}),
+				"regions": []interface{}{map[string]interface{}{"name": "cal"}},
+			},
+			"",
+		},
+		{
+			"ordered answer not known until apply",
+			map[string]interface{}{"answer": []interface{}{map[string]interface{}{"answer": unknownValue}}},
+			"",
+		},
+		{
+			"ordered answer in an undefined region",
+			map[string]interface{}{"answer": answer(map[string]interface{}{"region": "cal"})},
+			`region "cal"`,
+		},
+		{
+			"structured block of another type",
+			map[string]interface{}{"answers": []interface{}{map[string]interface{}{
+				"mx": []interface{}{map[string]interface{}{"preference": 10, "exchange": "mx.terraform.io."}},
+			}}},
+			"a mx block cannot be used in a A record",
+		},
+		{
+			"meta value and feed",
+			map[string]interface{}{"answers": answer(map[string]interface{}{
+				"meta": []interface{}{map[string]interface{}{"weight": 10, "weight_feed": "abc"}},
+			})},
+			"only one of weight and weight_feed",
+		},
+		{
+			"region meta value and feed",
+			map[string]interface{}{"regions": []interface{}{map[string]interface{}{
+				"name": "cal",
+				"meta": []interface{}{map[string]interface{}{"priority": 1, "priority_feed": "abc"}},
+			}}},
+			`region "cal"`,
+		},
+	}
+	for _, c := range cases {
+		_, err := testRecordDiff(t, testRecordConfig(c.config))
+		if c.err == "" {
+			if err != nil {
+				t.Errorf("%s: err: %s", c.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%s: expected an error containing %q, got %v", c.name, c.err, err)
+		}
+	}
+}
+
+// TestRecordLinkConflictsOnApply checks a link that was not known at plan
+// time, e.g. taken from another record created in the same apply.
+func TestRecordLinkConflictsOnApply(t *testing.T) {
+	for k, v := range map[string]interface{}{
+		"answers": []interface{}{map[string]interface{}{"answer": "1.2.3.4"}},
+		"answer":  []interface{}{map[string]interface{}{"answer": "1.2.3.4"}},
+		"filters": []interface{}{map[string]interface{}{"filter": "up"}},
+		"meta":    []interface{}{map[string]interface{}{"priority": "1"}},
+	} {
+		d := schema.TestResourceDataRaw(t, recordResource().Schema, testRecordConfig(map[string]interface{}{
+			"link": "other.terraform.io",
+			k:      v,
+		}))
+		err := resourceDataToRecord(NewRecord("terraform.io", "test.terraform.io", "A"), d)
+		if err == nil || !strings.Contains(err.Error(), "cannot have "+k+" of its own") {
+			t.Errorf("%s: expected an error, got %v", k, err)
+		}
+	}
+}
+
+// TestRecordDiffConflictsOnUpdate checks answers added to a record that
+// already has some, whose nested blocks are read differently from those of
+// a new record.
+func TestRecordDiffConflictsOnUpdate(t *testing.T) {
+	state := &terraform.InstanceState{ID: "test", Attributes: map[string]string{
+		"zone":                               "terraform.io",
+		"domain":                             "test.terraform.io",
+		"type":                               "MX",
+		"answers.#":                          "1",
+		"answers.1010235769.answer":          "10 mx.terraform.io.",
+		"answers.1010235769.mx.#":            "1",
+		"answers.1010235769.mx.0.preference": "10",
+		"answers.1010235769.mx.0.exchange":   "mx.terraform.io.",
+	}}
+	mx := func(preference int, exchange string) map[string]interface{} {
+		return map[string]interface{}{"mx": []interface{}{map[string]interface{}{"preference": preference, "exchange": exchange}}}
+	}
+	config := testRecordConfig(map[string]interface{}{
+		"type":    "MX",
+		"answers": []interface{}{mx(10, "mx.terraform.io."), mx(20, "mx2.terraform.io.")},
+	})
+	if _, err := recordResource().Diff(state, terraform.NewResourceConfigRaw(config), nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	config["answers"] = []interface{}{mx(10, "mx.terraform.io."), map[string]interface{}{
+		"srv": []interface{}{map[string]interface{}{"priority": 1, "weight": 1, "port": 25, "target": "mx2.terraform.io."}},
+	}}
+	_, err := recordResource().Diff(state, terraform.NewResourceConfigRaw(config), nil)
+	if err == nil || !strings.Contains(err.Error(), "a srv block cannot be used in a MX record") {
+		t.Fatalf("expected an error for the srv block, got %v", err)
+	}
+}
+
+func TestRecordDiffDomain(t *testing.T) {
+	for _, domain := range []string{"test.terraform.io", "TEST.terraform.io.", "test", "@"} {
+		if _, err := testRecordDiff(t, testRecordConfig(map[string]interface{}{"domain": domain})); err != nil {
+			t.Errorf("%s: err: %s", domain, err)
+		}
+	}
+
+	_, err := testRecordDiff(t, testRecordConfig(map[string]interface{}{"domain": "test.terraform.com"}))
+	if err == nil || !strings.Contains(err.Error(), "is not in zone") {
+		t.Fatalf("expected an error for a domain outside the zone, got %v", err)
+	}
+
+	// A relative domain matches the fully qualified domain read back into
+	// the state, so it does not replace the record.
+	state := &terraform.InstanceState{ID: "test", Attributes: map[string]string{
+		"zone":   "terraform.io",
+		"domain": "test.terraform.io",
+		"type":   "A",
+		"ttl":    "3600",
+	}}
+	diff, err := recordResource().Diff(state, terraform.NewResourceConfigRaw(testRecordConfig(map[string]interface{}{
+		"domain": "Test",
+		"ttl":    3600,
+	})), nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diff != nil && diff.RequiresNew() {
+		t.Fatalf("expected the record not to be replaced, got %#v", diff.Attributes)
+	}
+}
+
+func TestRecordDiffOrderedAnswers(t *testing.T) {
+	_, err := testRecordDiff(t, testRecordConfig(map[string]interface{}{
+		"type": "MX",
+		"answer": []interface{}{
+			map[string]interface{}{"mx": []interface{}{map[string]interface{}{"preference": 10, "exchange": "mx1.terraform.io."}}},
+			map[string]interface{}{"srv": []interface{}{map[string]interface{}{"priority": 1, "weight": 1, "port": 25, "target": "mx2.terraform.io."}}},
+		},
+	}))
+	if err == nil || !strings.Contains(err.Error(), "a srv block cannot be used in a MX record") {
+		t.Fatalf("expected an error for the srv block, got %v", err)
+	}
+
+	_, err = testRecordDiff(t, testRecordConfig(map[string]interface{}{
+		"link":   "other.terraform.io",
+		"answer": []interface{}{map[string]interface{}{"answer": "1.2.3.4"}},
+	}))
+	if err == nil || !strings.Contains(err.Error(), "cannot have answer of its own") {
+		t.Fatalf("expected an error for link with answer, got %v", err)
+	}
+
+	_, es := recordResource().Validate(terraform.NewResourceConfigRaw(testRecordConfig(map[string]interface{}{
+		"answer":  []interface{}{map[string]interface{}{"answer": "1.2.3.4"}},
+		"answers": []interface{}{map[string]interface{}{"answer": "1.2.3.5"}},
+	})))
+	if len(es) == 0 {
+		t.Fatalf("expected an error for both answer and answers")
+	}
+}
+
+func TestRecordOrderedAnswersRead(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, recordResource().Schema, testRecordConfig(map[string]interface{}{
+		"answer": []interface{}{
+			map[string]interface{}{"answer": "1.1.1.1"},
+			map[string]interface{}{"answer": "2.2.2.2"},
+		},
+	}))
+	r := NewRecord("terraform.io", "test.terraform.io", "A")
+	if err := resourceDataToRecord(r, d); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if r.Answers[0].Answer[0] != "1.1.1.1" || r.Answers[1].Answer[0] != "2.2.2.2" {
+		t.Fatalf("answers not sent in declared order: %+v", r.Answers)
+	}
+
+	// The API now holds them the other way round, which must show as drift.
+	r.Answers[0], r.Answers[1] = r.Answers[1], r.Answers[0]
+	if err := recordToResourceData(d, r); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if first := d.Get("answer.0.answer").(string); first != "2.2.2.2" {
+		t.Fatalf("expected answer.0 to be read back as 2.2.2.2, got %s", first)
+	}
+	if d.Get("answers").(*schema.Set).Len() != 0 {
+		t.Fatalf("expected answers to be left unset")
+	}
+}
+
+// TestRecordOrderedAnswerUpdate changes the answer string of an ordered
+// answer whose other forms are in the state from the last read.
+func TestRecordOrderedAnswerUpdate(t *testing.T) {
+	cases := []struct {
+		typ      string
+		old, new []string
+	}{
+		{"TXT", []string{"old"}, []string{"new"}},
+		{"MX", []string{"10", "mx1.terraform.io"}, []string{"20", "mx2.terraform.io"}},
+	}
+	for _, c := range cases {
+		config := func(answer string) map[string]interface{} {
+			return testRecordConfig(map[string]interface{}{
+				"type":   c.typ,
+				"ttl":    3600,
+				"answer": []interface{}{map[string]interface{}{"answer": answer}},
+			})
+		}
+		d := schema.TestResourceDataRaw(t, recordResource().Schema, config(strings.Join(c.old, " ")))
+		r := NewRecord("terraform.io", "test.terraform.io", c.typ)
+		r.Id = "test"
+		r.Ttl = 3600
+		r.UseClientSubnet = false
+		r.Answers = []nsone.Answer{{Answer: c.old}}
+		if err := recordToResourceData(d, r); err != nil {
+			t.Fatalf("%s: err: %s", c.typ, err)
+		}
+		state := d.State()
+		diff, err := recordResource().Diff(state, terraform.NewResourceConfigRaw(config(strings.Join(c.new, " "))), nil)
+		if err != nil {
+			t.Fatalf("%s: err: %s", c.typ, err)
+		}
+		d, err = schema.InternalMap(recordResource().Schema).Data(state, diff)
+		if err != nil {
+			t.Fatalf("%s: err: %s", c.typ, err)
+		}
+		r = NewRecord("terraform.io", "test.terraform.io", c.typ)
+		if err := resourceDataToRecord(r, d); err != nil {
+			t.Fatalf("%s: err: %s", c.typ, err)
+		}
+		if got := strings.Join(r.Answers[0].Answer, " "); got != strings.Join(c.new, " ") {
+			t.Fatalf("%s: expected answer %q to be sent, got %q", c.typ, strings.Join(c.new, " "), got)
+		}
+
+		// Read back, the answer as sent must plan no further change.
+		if err := recordToResourceData(d, r); err != nil {
+			t.Fatalf("%s: err: %s", c.typ, err)
+		}
+		diff, err = recordResource().Diff(d.State(), terraform.NewResourceConfigRaw(config(strings.Join(c.new, " "))), nil)
+		if err != nil {
+			t.Fatalf("%s: err: %s", c.typ, err)
+		}
+		if diff != nil && !diff.Empty() {
+			t.Fatalf("%s: expected no diff, got %#v", c.typ, diff.Attributes)
+		}
+	}
+}
+
+func TestRecordDiffCanonicalAnswers(t *testing.T) {
+	_, err := testRecordDiff(t, testRecordConfig(map[string]interface{}{
+		"answers": []interface{}{map[string]interface{}{"answer": "2001:db8::1"}},
+	}))
+	if err == nil || !strings.Contains(err.Error(), "must be an IPv4 address") {
+		t.Fatalf("expected an error for an IPv6 answer in an A record, got %v", err)
+	}
+
+	// The state holds the answer as the API returns it.
+	d := schema.TestResourceDataRaw(t, recordResource().Schema, testRecordConfig(map[string]interface{}{"type": "AAAA"}))
+	r := NewRecord("terraform.io", "test.terraform.io", "AAAA")
+	r.Id = "test"
+	r.Ttl = 3600
+	r.UseClientSubnet = false
+	r.Answers = []nsone.Answer{{Answer: []string{"2001:db8::1"}}}
+	if err := recordToResourceData(d, r); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	diff, err := recordResource().Diff(d.State(), terraform.NewResourceConfigRaw(testRecordConfig(map[string]interface{}{
+		"type":    "AAAA",
+		"ttl":     3600,
+		"answers": []interface{}{map[string]interface{}{"answer": "2001:0DB8:0:0::1"}},
+	})), nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diff != nil && !diff.Empty() {
+		t.Fatalf("expected no diff, got %#v", diff.Attributes)
+	}
+}
+
+func TestRecordDiffUnknownAnswer(t *testing.T) {
+	// An answer taken from another resource is not known until apply.
+	_, err := testRecordDiff(t, testRecordConfig(map[string]interface{}{
+		"answers": []interface{}{map[string]interface{}{"answer": unknownValue}},
+	}))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestRecordTXTAnswersDifferingInCase(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, recordResource().Schema, testRecordConfig(map[string]interface{}{
+		"type": "TXT",
+		"answers": []interface{}{
+			map[string]interface{}{"answer": "Hello.World"},
+			map[string]interface{}{"answer": "hello.world"},
+		},
+	}))
+	if n := d.Get("answers").(*schema.Set).Len(); n != 2 {
+		t.Fatalf("expected 2 answers, got %d", n)
+	}
+	r := NewRecord("terraform.io", "test.terraform.io", "TXT")
+	if err := resourceDataToRecord(r, d); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(r.Answers) != 2 {
+		t.Fatalf("expected 2 answers to be sent, got %+v", r.Answers)
+	}
+}
+
+func TestRecordHostnameSpellingKept(t *testing.T) {
+	config := testRecordConfig(map[string]interface{}{
+		"type": "MX",
+		"ttl":  3600,
+		"answers": []interface{}{
+			map[string]interface{}{"answer": "10 MX1.terraform.io."},
+			map[string]interface{}{"mx": []interface{}{map[string]interface{}{"preference": 20, "exchange": "MX2.terraform.io."}}},
+		},
+	})
+	d := schema.TestResourceDataRaw(t, recordResource().Schema, config)
+	r := NewRecord("terraform.io", "test.terraform.io", "MX")
+	if err := resourceDataToRecord(r, d); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	// Read back as the API returns it, lower case and without the dot.
+	r.Id = "test"
+	r.Ttl = 3600
+	r.UseClientSubnet = false
+	r.Answers = []nsone.Answer{{Answer: []string{"10", "mx1.terraform.io"}}, {Answer: []string{"20", "mx2.terraform.io"}}}
+	if err := recordToResourceData(d, r); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	diff, err := recordResource().Diff(d.State(), terraform.NewResourceConfigRaw(config), nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diff != nil && !diff.Empty() {
+		t.Fatalf("expected no diff, got %#v", diff.Attributes)
+	}
+}
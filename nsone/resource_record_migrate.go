@@ -0,0 +1,409 @@
+package nsone
+
+import (
+	"encoding/json"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// recordResourceV0 is the schema of nsone_record before answer metadata
+// became a typed block. It is only used to decode old state.
+func recordResourceV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"zone": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"domain": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"ttl": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"type": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"meta": &schema.Schema{
+				Type:     schema.TypeMap,
+				Optional: true,
+			},
+			"link": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"use_client_subnet": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"answers": &schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"answer": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"region": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"meta": &schema.Schema{
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"field": &schema.Schema{
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"feed": &schema.Schema{
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"value": &schema.Schema{
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"regions": &schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"georegion": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"country": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"us_state": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"longitude": &schema.Schema{
+							Type:     schema.TypeFloat,
+							Optional: true,
+						},
+						"latitude": &schema.Schema{
+							Type:     schema.TypeFloat,
+							Optional: true,
+						},
+						"up": &schema.Schema{
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"filters": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"filter": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"disabled": &schema.Schema{
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+						"config": &schema.Schema{
+							Type:     schema.TypeMap,
+							Optional: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// recordStateUpgradeV0 turns the field/feed/value meta sets of each answer
+// into typed meta blocks.
+func recordStateUpgradeV0(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	answers, _ := rawState["answers"].([]interface{})
+	for _, raw := range answers {
+		answer, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		metas, _ := answer["meta"].([]interface{})
+		if len(metas) == 0 {
+			answer["meta"] = []interface{}{}
+			continue
+		}
+		answer["meta"] = []interface{}{metaV0ToBlock(metas)}
+	}
+	return rawState, nil
+}
+
+// metaV0ToBlock converts a set of field/feed/value metas to a typed meta
+// block. Values were strings, with lists joined by commas.
+func metaV0ToBlock(metas []interface{}) map[string]interface{} {
+	old := make(map[string]interface{}, len(metas))
+	for _, raw := range metas {
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		field, _ := m["field"].(string)
+		if feed, _ := m["feed"].(string); feed != "" {
+			old[field] = map[string]interface{}{"feed": feed}
+		} else if value, _ := m["value"].(string); value != "" {
+			old[field] = value
+		}
+	}
+	for field := range old {
+		if !isMetaField(field) {
+			log.Printf("[WARN] Dropping unknown answer meta field %q from state", field)
+		}
+	}
+	return metaAPIToBlock(old)
+}
+
+// recordResourceV1 is the schema of nsone_record before record metadata
+// became a typed block.
+func recordResourceV1() *schema.Resource {
+	r := recordResourceV0()
+	answer := answerSchema()
+	answer["meta"] = metaBlockSchemaV3()
+	r.Schema["answers"].Elem = &schema.Resource{
+		Schema: answer,
+	}
+	return r
+}
+
+// recordStateUpgradeV1 turns the record's meta map of strings into a typed
+// meta block.
+func recordStateUpgradeV1(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	old, _ := rawState["meta"].(map[string]interface{})
+	if len(old) == 0 {
+		rawState["meta"] = []interface{}{}
+		return rawState, nil
+	}
+	for field := range old {
+		if !isMetaField(field) {
+			log.Printf("[WARN] Dropping unknown record meta field %q from state", field)
+		}
+	}
+	rawState["meta"] = []interface{}{metaAPIToBlock(old)}
+	return rawState, nil
+}
+
+// recordResourceV2 is the schema of nsone_record before region metadata
+// became a typed block.
+func recordResourceV2() *schema.Resource {
+	r := recordResourceV1()
+	r.Schema["meta"] = metaBlockSchemaV3()
+	return r
+}
+
+// recordStateUpgradeV2 moves the georegion, country, us_state, latitude and
+// longitude of each region into a typed meta block. A latitude or longitude
+// of 0 is kept, being on the equator or the prime meridian; if it was not
+// set, the next refresh reads it as unset. An up of false was never sent,
+// so up is left at its default of true.
+func recordStateUpgradeV2(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	regions, _ := rawState["regions"].([]interface{})
+	for _, raw := range regions {
+		region, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		old := make(map[string]interface{})
+		for _, k := range []string{"georegion", "country", "us_state"} {
+			if v, _ := region[k].(string); v != "" {
+				old[k] = []interface{}{v}
+			}
+			delete(region, k)
+		}
+		for _, k := range []string{"latitude", "longitude"} {
+			if v, ok := region[k].(float64); ok {
+				old[k] = v
+			}
+			delete(region, k)
+		}
+		delete(region, "up")
+		if len(old) == 0 {
+			region["meta"] = []interface{}{}
+			continue
+		}
+		region["meta"] = []interface{}{metaAPIToBlock(old)}
+	}
+	return rawState, nil
+}
+
+// recordResourceV3 is the schema of nsone_record before the numbers in meta
+// blocks were held as strings.
+func recordResourceV3() *schema.Resource {
+	r := recordResourceV4()
+	r.Schema["meta"] = metaBlockSchemaV3()
+	for _, k := range []string{"answers", "answer", "regions"} {
+		r.Schema[k].Elem.(*schema.Resource).Schema["meta"] = metaBlockSchemaV3()
+	}
+	return r
+}
+
+// metaBlockSchemaV3 is the schema of a meta block before its numbers were
+// held as strings.
+func metaBlockSchemaV3() *schema.Schema {
+	s := metaBlockSchema()
+	block := s.Elem.(*schema.Resource).Schema
+	for _, f := range metaFields {
+		if f.isNumber() {
+			block[f.name] = &schema.Schema{
+				Type:     f.typ,
+				Optional: true,
+			}
+		}
+	}
+	return s
+}
+
+// recordStateUpgradeV3 turns the numbers in the record's meta blocks into
+// strings. A zero was never sent, since it could not be told apart from an
+// unset number, so it is dropped. Numbers that are strings already come
+// from the earlier upgrades, which only kept numbers that were set.
+func recordStateUpgradeV3(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	metaNumbersV3ToStrings(rawState["meta"])
+	for _, k := range []string{"answers", "answer", "regions"} {
+		l, _ := rawState[k].([]interface{})
+		for _, raw := range l {
+			if m, ok := raw.(map[string]interface{}); ok {
+				metaNumbersV3ToStrings(m["meta"])
+			}
+		}
+	}
+	return rawState, nil
+}
+
+func metaNumbersV3ToStrings(v interface{}) {
+	block := metaBlock(v)
+	if block == nil {
+		return
+	}
+	for _, f := range metaFields {
+		if !f.isNumber() {
+			continue
+		}
+		switch n := block[f.name].(type) {
+		case string:
+		case float64:
+			if n == 0 {
+				delete(block, f.name)
+			} else {
+				c, _ := metaValue(f.typ, n)
+				block[f.name] = metaNumberString(c)
+			}
+		default:
+			delete(block, f.name)
+		}
+	}
+}
+
+// recordResourceV4 is the schema of nsone_record before the config of a
+// filter became a typed block.
+func recordResourceV4() *schema.Resource {
+	r := &schema.Resource{Schema: recordSchema()}
+	filter := r.Schema["filters"].Elem.(*schema.Resource).Schema
+	filter["config"] = &schema.Schema{
+		Type:     schema.TypeMap,
+		Optional: true,
+	}
+	delete(filter, "config_json")
+	return r
+}
+
+// recordStateUpgradeV4 turns the config map of strings of each filter into
+// a typed config block. If any of it is not described by the catalog for
+// the filter, or does not parse as the type described, the whole config is
+// moved to config_json as it was instead; the next refresh reads it as the
+// API holds it.
+func recordStateUpgradeV4(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	filters, _ := rawState["filters"].([]interface{})
+	for _, raw := range filters {
+		fi, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		old, _ := fi["config"].(map[string]interface{})
+		fi["config"] = []interface{}{}
+		if len(old) == 0 {
+			continue
+		}
+		name, _ := fi["filter"].(string)
+		block, ok := filterConfigV4ToBlock(name, old)
+		if !ok {
+			b, _ := json.Marshal(old)
+			fi["config_json"] = string(b)
+			log.Printf("[WARN] Moving the config of filter %s to config_json in state", name)
+			continue
+		}
+		if len(block) > 0 {
+			fi["config"] = []interface{}{block}
+		}
+	}
+	return rawState, nil
+}
+
+func filterConfigV4ToBlock(filter string, old map[string]interface{}) (map[string]interface{}, bool) {
+	block := make(map[string]interface{})
+	for k, raw := range old {
+		key, ok := filterTypes[filter][k]
+		if !ok {
+			return nil, false
+		}
+		v, _ := raw.(string)
+		var value interface{}
+		var err error
+		switch key.typ {
+		case schema.TypeBool:
+			value, err = strconv.ParseBool(v)
+		case schema.TypeInt:
+			value, err = strconv.Atoi(v)
+		case schema.TypeString:
+			if len(key.allowed) > 0 && !stringInSlice(v, key.allowed) {
+				return nil, false
+			}
+			value = v
+		default:
+			return nil, false
+		}
+		if err != nil {
+			return nil, false
+		}
+		if !isZeroFilterValue(value) {
+			block[filterConfigAttr(k)] = value
+		}
+	}
+	return block, true
+}
+
+func isMetaField(name string) bool {
+	for _, f := range metaFields {
+		if f.name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,198 @@
+package nsone
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRecordStateUpgradeV0(t *testing.T) {
+	raw := map[string]interface{}{
+		"zone": "terraform.io",
+		"answers": []interface{}{
+			map[string]interface{}{
+				"answer": "1.2.3.4",
+				"meta": []interface{}{
+					map[string]interface{}{"field": "weight", "value": "10", "feed": ""},
+					map[string]interface{}{"field": "up", "feed": "feed123", "value": ""},
+					map[string]interface{}{"field": "country", "value": "US,CA", "feed": ""},
+				},
+			},
+			map[string]interface{}{
+				"answer": "5.6.7.8",
+				"meta":   []interface{}{},
+			},
+		},
+	}
+	upgraded, err := recordStateUpgradeV0(raw, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	answers := upgraded["answers"].([]interface{})
+	block := metaBlock(answers[0].(map[string]interface{})["meta"])
+	expected := map[string]interface{}{
+		"up":      true,
+		"up_feed": "feed123",
+		"weight":  "10",
+		"country": []interface{}{"US", "CA"},
+	}
+	if !reflect.DeepEqual(block, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, block)
+	}
+	if block := metaBlock(answers[1].(map[string]interface{})["meta"]); block != nil {
+		t.Fatalf("expected no meta block, got %#v", block)
+	}
+}
+
+func TestRecordStateUpgradeV1(t *testing.T) {
+	raw := map[string]interface{}{
+		"zone": "terraform.io",
+		"meta": map[string]interface{}{
+			"up":       "0",
+			"priority": "5",
+			"foo":      "bar",
+		},
+	}
+	upgraded, err := recordStateUpgradeV1(raw, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]interface{}{
+		"up":       false,
+		"priority": "5",
+	}
+	if block := metaBlock(upgraded["meta"]); !reflect.DeepEqual(block, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, block)
+	}
+
+	upgraded, err = recordStateUpgradeV1(map[string]interface{}{"zone": "terraform.io"}, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if block := metaBlock(upgraded["meta"]); block != nil {
+		t.Fatalf("expected no meta block, got %#v", block)
+	}
+}
+
+func TestRecordStateUpgradeV2(t *testing.T) {
+	raw := map[string]interface{}{
+		"zone": "terraform.io",
+		"regions": []interface{}{
+			map[string]interface{}{
+				"name":      "cal",
+				"georegion": "US-WEST",
+				"country":   "",
+				"us_state":  "CA",
+				"latitude":  37.8,
+				"longitude": 0.0,
+				"up":        false,
+			},
+			map[string]interface{}{
+				"name":      "equator",
+				"georegion": "",
+				"country":   "",
+				"us_state":  "",
+				"latitude":  0.0,
+				"longitude": 0.0,
+				"up":        false,
+			},
+		},
+	}
+	upgraded, err := recordStateUpgradeV2(raw, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	regions := upgraded["regions"].([]interface{})
+	cal := regions[0].(map[string]interface{})
+	expected := map[string]interface{}{
+		"up":        true,
+		"georegion": []interface{}{"US-WEST"},
+		"us_state":  []interface{}{"CA"},
+		"latitude":  "37.8",
+		"longitude": "0",
+	}
+	if block := metaBlock(cal["meta"]); !reflect.DeepEqual(block, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, block)
+	}
+	if _, ok := cal["georegion"]; ok {
+		t.Fatalf("expected the old attributes to be removed, got %#v", cal)
+	}
+	expected = map[string]interface{}{
+		"up":        true,
+		"latitude":  "0",
+		"longitude": "0",
+	}
+	if block := metaBlock(regions[1].(map[string]interface{})["meta"]); !reflect.DeepEqual(block, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, block)
+	}
+}
+
+func TestRecordStateUpgradeV3(t *testing.T) {
+	block := func(m map[string]interface{}) []interface{} {
+		return []interface{}{m}
+	}
+	raw := map[string]interface{}{
+		"zone": "terraform.io",
+		"meta": block(map[string]interface{}{"up": true, "priority": 2.0, "weight": 0.0, "latitude": 0.0}),
+		"answers": []interface{}{
+			map[string]interface{}{
+				"answer": "1.2.3.4",
+				"meta":   block(map[string]interface{}{"up": true, "weight": 0.5, "connections": 0.0}),
+			},
+		},
+		"regions": []interface{}{
+			map[string]interface{}{
+				"name": "cal",
+				// Set by recordStateUpgradeV2.
+				"meta": block(map[string]interface{}{"up": true, "latitude": "0"}),
+			},
+		},
+	}
+	upgraded, err := recordStateUpgradeV3(raw, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]interface{}{"up": true, "priority": "2"}
+	if b := metaBlock(upgraded["meta"]); !reflect.DeepEqual(b, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, b)
+	}
+	expected = map[string]interface{}{"up": true, "weight": "0.5"}
+	answer := upgraded["answers"].([]interface{})[0].(map[string]interface{})
+	if b := metaBlock(answer["meta"]); !reflect.DeepEqual(b, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, b)
+	}
+	expected = map[string]interface{}{"up": true, "latitude": "0"}
+	region := upgraded["regions"].([]interface{})[0].(map[string]interface{})
+	if b := metaBlock(region["meta"]); !reflect.DeepEqual(b, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, b)
+	}
+}
+
+func TestRecordStateUpgradeV4(t *testing.T) {
+	raw := map[string]interface{}{
+		"zone": "terraform.io",
+		"filters": []interface{}{
+			map[string]interface{}{"filter": "up"},
+			map[string]interface{}{"filter": "select_first_n", "config": map[string]interface{}{"N": "2"}},
+			map[string]interface{}{"filter": "geofence_country", "config": map[string]interface{}{"remove_no_location": "false"}},
+			map[string]interface{}{"filter": "pulsar_sort", "config": map[string]interface{}{"job": "abc"}},
+		},
+	}
+	upgraded, err := recordStateUpgradeV4(raw, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	filters := upgraded["filters"].([]interface{})
+	for i, expected := range []interface{}{
+		[]interface{}{},
+		[]interface{}{map[string]interface{}{"n": 2}},
+		[]interface{}{},
+		[]interface{}{},
+	} {
+		if c := filters[i].(map[string]interface{})["config"]; !reflect.DeepEqual(c, expected) {
+			t.Fatalf("filters.%d: expected %#v, got %#v", i, expected, c)
+		}
+	}
+	if c := filters[3].(map[string]interface{})["config_json"]; c != `{"job":"abc"}` {
+		t.Fatalf("expected the pulsar config in config_json, got %#v", c)
+	}
+}
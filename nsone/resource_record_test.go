@@ -2,15 +2,15 @@ package nsone
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
-	nsone "gopkg.in/sarguru/ns1-go.v18"
 )
 
 func TestAccRecord_basic(t *testing.T) {
-	var record nsone.Record
+	var record Record
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
@@ -29,7 +29,7 @@ func TestAccRecord_basic(t *testing.T) {
 }
 
 func TestAccRecord_updated(t *testing.T) {
-	var record nsone.Record
+	var record Record
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
@@ -55,6 +55,129 @@ func TestAccRecord_updated(t *testing.T) {
 	})
 }
 
+func TestAccRecord_structured(t *testing.T) {
+	var record Record
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRecordDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccRecord_structured,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordState("type", "CAA"),
+					testAccCheckRecordExists("nsone_record.foobar", &record),
+					testAccCheckRecordAnswerRdata(&record, "0", "issue", "letsencrypt.org"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRecord_structuredMX(t *testing.T) {
+	var record Record
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRecordDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccRecord_structuredMX,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordState("type", "MX"),
+					testAccCheckRecordExists("nsone_record.foobar", &record),
+					testAccCheckRecordAnswerRdata(&record, "10", "mx1.terraform.io"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRecord_txtParts(t *testing.T) {
+	var record Record
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRecordDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccRecord_txtParts,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordState("type", "TXT"),
+					testAccCheckRecordExists("nsone_record.foobar", &record),
+					testAccCheckRecordAnswerRdata(&record, "v=spf1 include:_spf.terraform.io", " include:_spf2.terraform.io -all"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRecord_meta(t *testing.T) {
+	var record Record
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRecordDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccRecord_meta,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists("nsone_record.foobar", &record),
+					testAccCheckRecordMeta(&record, "high_watermark", float64(100)),
+					testAccCheckRecordMeta(&record, "up", false),
+					resource.TestCheckResourceAttr("nsone_record.foobar", "meta.0.low_watermark", "50"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccRecord_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists("nsone_record.foobar", &record),
+					testAccCheckRecordMeta(&record, "high_watermark", nil),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRecord_regions(t *testing.T) {
+	var record Record
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRecordDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccRecord_regions,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists("nsone_record.foobar", &record),
+					testAccCheckRecordRegionMeta(&record, "sa", "latitude", -33.4489),
+					testAccCheckRecordRegionMeta(&record, "sa", "longitude", -70.6693),
+					testAccCheckRecordRegionMeta(&record, "sa", "weight", float64(2)),
+					testAccCheckRecordRegionMeta(&record, "sa", "country", []interface{}{"CL", "AR"}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRecord_orderedAnswers(t *testing.T) {
+	var record Record
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRecordDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccRecord_orderedAnswers,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists("nsone_record.foobar", &record),
+					testAccCheckRecordAnswerOrder(&record, "3.3.3.3", "1.1.1.1", "2.2.2.2"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckRecordState(key, value string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources["nsone_record.foobar"]
@@ -76,7 +199,7 @@ func testAccCheckRecordState(key, value string) resource.TestCheckFunc {
 	}
 }
 
-func testAccCheckRecordExists(n string, record *nsone.Record) resource.TestCheckFunc {
+func testAccCheckRecordExists(n string, record *Record) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
 
@@ -88,7 +211,7 @@ func testAccCheckRecordExists(n string, record *nsone.Record) resource.TestCheck
 			return fmt.Errorf("NoID is set")
 		}
 
-		client := testAccProvider.Meta().(*nsone.APIClient)
+		client := testAccProvider.Meta().(*Client)
 
 		p := rs.Primary
 
@@ -110,7 +233,7 @@ func testAccCheckRecordExists(n string, record *nsone.Record) resource.TestCheck
 }
 
 func testAccCheckRecordDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*nsone.APIClient)
+	client := testAccProvider.Meta().(*Client)
 
 	var recordDomain string
 	var recordZone string
@@ -137,7 +260,7 @@ func testAccCheckRecordDestroy(s *terraform.State) error {
 	return nil
 }
 
-func testAccCheckRecordAttributes(record *nsone.Record) resource.TestCheckFunc {
+func testAccCheckRecordAttributes(record *Record) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 
 		if record.Ttl != 60 {
@@ -165,7 +288,7 @@ func testAccCheckRecordAttributes(record *nsone.Record) resource.TestCheckFunc {
 	}
 }
 
-func testAccCheckRecordAttributesUpdated(record *nsone.Record) resource.TestCheckFunc {
+func testAccCheckRecordAttributesUpdated(record *Record) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 
 		if record.Ttl != 120 {
@@ -193,6 +316,60 @@ func testAccCheckRecordAttributesUpdated(record *nsone.Record) resource.TestChec
 	}
 }
 
+func testAccCheckRecordAnswerRdata(record *Record, rdata ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if len(record.Answers) != 1 {
+			return fmt.Errorf("Bad number of answers : %d", len(record.Answers))
+		}
+		answer := record.Answers[0].Answer
+		if len(answer) != len(rdata) {
+			return fmt.Errorf("Bad value : %v", answer)
+		}
+		for i := range rdata {
+			if answer[i] != rdata[i] {
+				return fmt.Errorf("Bad value : %v", answer)
+			}
+		}
+		return nil
+	}
+}
+
+func testAccCheckRecordAnswerOrder(record *Record, answers ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if len(record.Answers) != len(answers) {
+			return fmt.Errorf("Bad number of answers : %d", len(record.Answers))
+		}
+		for i, a := range record.Answers {
+			if a.Answer[0] != answers[i] {
+				return fmt.Errorf("Bad answer %d : %v", i, a.Answer)
+			}
+		}
+		return nil
+	}
+}
+
+func testAccCheckRecordMeta(record *Record, key string, value interface{}) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if record.Meta[key] != value {
+			return fmt.Errorf("Bad value for meta %s : %v", key, record.Meta[key])
+		}
+		return nil
+	}
+}
+
+func testAccCheckRecordRegionMeta(record *Record, region, key string, value interface{}) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		r, ok := record.Regions[region]
+		if !ok {
+			return fmt.Errorf("Region not found : %s", region)
+		}
+		if !reflect.DeepEqual(r.Meta[key], value) {
+			return fmt.Errorf("Bad value for region %s meta %s : %v", region, key, r.Meta[key])
+		}
+		return nil
+	}
+}
+
 const testAccRecord_basic = `
 resource "nsone_record" "foobar" {
     zone = "terraform.io"
@@ -204,33 +381,29 @@ resource "nsone_record" "foobar" {
       answer = "test1.terraform.io"
       region = "cal"
       meta {
-        field = "weight"
-        value = "10"
-      }
-      meta {
-        field = "up"
-        value = "1"
+        weight = 10
+        up = true
       }
     }
     answers {
       answer = "test2.terraform.io"
       region = "ny"
       meta {
-        field = "weight"
-        value = "10"
-      }
-      meta {
-        field = "up"
-        value = "1"
+        weight = 10
+        up = true
       }
     }
     regions {
       name = "cal"
-      us_state = "CA"
+      meta {
+        us_state = ["CA"]
+      }
     }
     regions {
       name = "ny"
-      us_state = "NY"
+      meta {
+        us_state = ["NY"]
+      }
     }
 
     filters {
@@ -255,33 +428,29 @@ resource "nsone_record" "foobar" {
 		answer = "test3.terraform.io"
 		region = "wa"
 		meta {
-			field = "weight"
-			value = "5"
-		}
-		meta {
-			field = "up"
-			value = "1"
+			weight = 5
+			up = true
 		}
 	}
 	answers {
 		answer = "test2.terraform.io"
 		region = "ny"
 		meta {
-			field = "weight"
-			value = "10"
-		}
-		meta {
-			field = "up"
-			value = "1"
+			weight = 10
+			up = true
 		}
 	}
 	regions {
 		name = "wa"
-		us_state = "WA"
+		meta {
+			us_state = ["WA"]
+		}
 	}
 	regions {
 		name = "ny"
-		us_state = "NY"
+		meta {
+			us_state = ["NY"]
+		}
 	}
 
 	filters {
@@ -294,3 +463,120 @@ resource "nsone_record" "foobar" {
 resource "nsone_zone" "test" {
 	zone = "terraform.io"
 }`
+
+const testAccRecord_structured = `
+resource "nsone_record" "foobar" {
+	zone = "${nsone_zone.test.zone}"
+	domain = "test.terraform.io"
+	type = "CAA"
+	answers {
+		caa {
+			flags = 0
+			tag = "issue"
+			value = "letsencrypt.org"
+		}
+	}
+}
+resource "nsone_zone" "test" {
+	zone = "terraform.io"
+}`
+
+const testAccRecord_structuredMX = `
+resource "nsone_record" "foobar" {
+	zone = "${nsone_zone.test.zone}"
+	domain = "terraform.io"
+	type = "MX"
+	answers {
+		mx {
+			preference = 10
+			exchange = "mx1.terraform.io"
+		}
+	}
+}
+resource "nsone_zone" "test" {
+	zone = "terraform.io"
+}`
+
+const testAccRecord_txtParts = `
+resource "nsone_record" "foobar" {
+	zone = "${nsone_zone.test.zone}"
+	domain = "terraform.io"
+	type = "TXT"
+	answers {
+		answer_parts = ["v=spf1 include:_spf.terraform.io", " include:_spf2.terraform.io -all"]
+	}
+}
+resource "nsone_zone" "test" {
+	zone = "terraform.io"
+}`
+
+const testAccRecord_orderedAnswers = `
+resource "nsone_record" "foobar" {
+	zone = "${nsone_zone.test.zone}"
+	domain = "test.terraform.io"
+	type = "A"
+	answer {
+		answer = "3.3.3.3"
+	}
+	answer {
+		answer = "1.1.1.1"
+	}
+	answer {
+		answer = "2.2.2.2"
+	}
+	filters {
+		filter = "select_first_n"
+		config {
+			n = 1
+		}
+	}
+}
+resource "nsone_zone" "test" {
+	zone = "terraform.io"
+}`
+
+const testAccRecord_meta = `
+resource "nsone_record" "foobar" {
+	zone = "${nsone_zone.test.zone}"
+	domain = "test.terraform.io"
+	type = "CNAME"
+	ttl = 60
+	meta {
+		up = false
+		high_watermark = 100
+		low_watermark = 50
+	}
+	answers {
+		answer = "test1.terraform.io"
+	}
+}
+resource "nsone_zone" "test" {
+	zone = "terraform.io"
+}`
+
+const testAccRecord_regions = `
+resource "nsone_record" "foobar" {
+	zone = "${nsone_zone.test.zone}"
+	domain = "test.terraform.io"
+	type = "CNAME"
+	answers {
+		answer = "test1.terraform.io"
+		region = "sa"
+	}
+	regions {
+		name = "sa"
+		meta {
+			georegion = ["SOUTH-AMERICA"]
+			country = ["CL", "AR"]
+			latitude = -33.4489
+			longitude = -70.6693
+			weight = 2
+		}
+	}
+	filters {
+		filter = "geotarget_country"
+	}
+}
+resource "nsone_zone" "test" {
+	zone = "terraform.io"
+}`
@@ -19,6 +19,9 @@ func teamResource() *schema.Resource {
 		Read:   TeamRead,
 		Update: TeamUpdate,
 		Delete: TeamDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 	}
 }
 
@@ -37,7 +40,7 @@ func resourceDataToTeam(u *nsone.Team, d *schema.ResourceData) error {
 }
 
 func TeamCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	mj := nsone.Team{}
 	if err := resourceDataToTeam(&mj, d); err != nil {
 		return err
@@ -49,24 +52,24 @@ func TeamCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func TeamRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	mj, err := client.GetTeam(d.Id())
 	if err != nil {
-		return err
+		return readError(d, "team", err)
 	}
 	teamToResourceData(d, &mj)
 	return nil
 }
 
 func TeamDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	err := client.DeleteTeam(d.Id())
 	d.SetId("")
 	return err
 }
 
 func TeamUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	mj := nsone.Team{
 		Id: d.Id(),
 	}
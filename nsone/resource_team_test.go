@@ -0,0 +1,55 @@
+package nsone
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccTeam_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckTeamDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccTeam_basic,
+			},
+			resource.TestStep{
+				ResourceName:      "nsone_team.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckTeamDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "nsone_team" {
+			continue
+		}
+
+		_, err := client.GetTeam(rs.Primary.ID)
+
+		if !isNotFound(err) {
+			return fmt.Errorf("Team still exists")
+		}
+	}
+
+	return nil
+}
+
+const testAccTeam_basic = `
+resource "nsone_team" "foobar" {
+	name = "terraform test"
+	dns_view_zones = true
+	dns_zones_allow_by_default = true
+	dns_zones_deny = ["mytest.terraform.io"]
+	data_manage_datasources = true
+	monitoring_view_jobs = true
+}`
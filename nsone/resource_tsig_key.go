@@ -0,0 +1,107 @@
+package nsone
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func tsigKeyResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"algorithm": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(tsigAlgorithms, false),
+			},
+			"secret": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				Sensitive:    true,
+				ValidateFunc: validateTsigSecret,
+			},
+		},
+		Create: TsigKeyCreate,
+		Read:   TsigKeyRead,
+		Update: TsigKeyUpdate,
+		Delete: TsigKeyDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+	}
+}
+
+// validateTsigSecret checks that a TSIG secret is base64 encoded, without
+// putting the secret in the error.
+func validateTsigSecret(v interface{}, k string) ([]string, []error) {
+	s, _ := v.(string)
+	if _, err := base64.StdEncoding.DecodeString(s); err != nil {
+		return nil, []error{fmt.Errorf("%s must be base64 encoded", k)}
+	}
+	return nil, nil
+}
+
+func tsigKeyToResourceData(d *schema.ResourceData, k *TsigKey) error {
+	d.SetId(k.Name)
+	d.Set("name", k.Name)
+	d.Set("algorithm", k.Algorithm)
+	// The API may not return the secret; keep the one in the state if so.
+	if k.Secret != "" {
+		d.Set("secret", k.Secret)
+	}
+	return nil
+}
+
+func resourceDataToTsigKey(k *TsigKey, d *schema.ResourceData) error {
+	k.Name = d.Get("name").(string)
+	k.Algorithm = d.Get("algorithm").(string)
+	k.Secret = d.Get("secret").(string)
+	return nil
+}
+
+func TsigKeyCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	k := TsigKey{}
+	if err := resourceDataToTsigKey(&k, d); err != nil {
+		return err
+	}
+	if err := client.CreateTsigKey(&k); err != nil {
+		return err
+	}
+	return tsigKeyToResourceData(d, &k)
+}
+
+func TsigKeyRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	k, err := client.GetTsigKey(d.Id())
+	if err != nil {
+		return readError(d, "TSIG key", err)
+	}
+	return tsigKeyToResourceData(d, k)
+}
+
+func TsigKeyDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	err := client.DeleteTsigKey(d.Id())
+	d.SetId("")
+	return err
+}
+
+func TsigKeyUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	k := TsigKey{}
+	if err := resourceDataToTsigKey(&k, d); err != nil {
+		return err
+	}
+	if err := client.UpdateTsigKey(&k); err != nil {
+		return err
+	}
+	return tsigKeyToResourceData(d, &k)
+}
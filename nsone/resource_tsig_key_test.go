@@ -0,0 +1,71 @@
+package nsone
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccTsigKey_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckTsigKeyDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccTsigKey_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("nsone_tsig_key.foobar", "id", "terraform-test"),
+					resource.TestCheckResourceAttr("nsone_tsig_key.foobar", "algorithm", "hmac-sha256"),
+					resource.TestCheckResourceAttr("nsone_zone.foobar", "secondary.0.tsig.0.name", "terraform-test"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:      "nsone_tsig_key.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// The API may not return the secret.
+				ImportStateVerifyIgnore: []string{"secret"},
+			},
+		},
+	})
+}
+
+func testAccCheckTsigKeyDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "nsone_tsig_key" {
+			continue
+		}
+
+		_, err := client.GetTsigKey(rs.Primary.ID)
+
+		if !isNotFound(err) {
+			return fmt.Errorf("TSIG key still exists")
+		}
+	}
+
+	return nil
+}
+
+const testAccTsigKey_basic = `
+resource "nsone_tsig_key" "foobar" {
+	name = "terraform-test"
+	algorithm = "hmac-sha256"
+	secret = "dGVycmFmb3JtIHRlc3Qgc2VjcmV0"
+}
+
+resource "nsone_zone" "foobar" {
+	zone = "terraform.io"
+	secondary {
+		primary_ip = "1.1.1.1"
+		tsig {
+			name = "${nsone_tsig_key.foobar.name}"
+			algorithm = "${nsone_tsig_key.foobar.algorithm}"
+			key = "${nsone_tsig_key.foobar.secret}"
+		}
+	}
+}`
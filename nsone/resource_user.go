@@ -126,6 +126,9 @@ func userResource() *schema.Resource {
 		Read:   UserRead,
 		Update: UserUpdate,
 		Delete: UserDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 	}
 }
 
@@ -153,6 +156,7 @@ func permissionsToResourceData(d *schema.ResourceData, permissions nsone.Permiss
 
 func userToResourceData(d *schema.ResourceData, u *nsone.User) error {
 	d.SetId(u.Username)
+	d.Set("username", u.Username)
 	d.Set("name", u.Name)
 	d.Set("email", u.Email)
 	d.Set("teams", u.Teams)
@@ -185,7 +189,7 @@ func resourceDataToUser(u *nsone.User, d *schema.ResourceData) error {
 }
 
 func UserCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	mj := nsone.User{}
 	if err := resourceDataToUser(&mj, d); err != nil {
 		return err
@@ -197,24 +201,24 @@ func UserCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func UserRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	mj, err := client.GetUser(d.Id())
 	if err != nil {
-		return err
+		return readError(d, "user", err)
 	}
 	userToResourceData(d, &mj)
 	return nil
 }
 
 func UserDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	err := client.DeleteUser(d.Id())
 	d.SetId("")
 	return err
 }
 
 func UserUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	mj := nsone.User{
 		Username: d.Id(),
 	}
@@ -0,0 +1,58 @@
+package nsone
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccUser_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckUserDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccUser_basic,
+			},
+			resource.TestStep{
+				ResourceName:      "nsone_user.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckUserDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "nsone_user" {
+			continue
+		}
+
+		_, err := client.GetUser(rs.Primary.ID)
+
+		if !isNotFound(err) {
+			return fmt.Errorf("User still exists")
+		}
+	}
+
+	return nil
+}
+
+const testAccUser_basic = `
+resource "nsone_team" "test" {
+	name = "terraform test"
+	dns_view_zones = true
+}
+
+resource "nsone_user" "foobar" {
+	name = "Terraform Test"
+	username = "terraform_test"
+	email = "terraform_test@example.com"
+	teams = ["${nsone_team.test.id}"]
+}`
@@ -5,7 +5,7 @@ import (
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-	nsone "gopkg.in/sarguru/ns1-go.v18"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 )
 
 func zoneResource() *schema.Resource {
@@ -63,9 +63,111 @@ func zoneResource() *schema.Resource {
 				Default:  "0",
 			},
 			"primary": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Deprecated:    "use the secondary block instead",
+				ConflictsWith: []string{"secondary"},
+			},
+			"secondary": &schema.Schema{
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"primary"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"primary_ip": &schema.Schema{
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.IsIPAddress,
+						},
+						"primary_port": &schema.Schema{
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      53,
+							ValidateFunc: validation.IsPortNumber,
+						},
+						"other_ips": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validation.IsIPAddress,
+							},
+						},
+						"tsig": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"name": &schema.Schema{
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"algorithm": &schema.Schema{
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validation.StringInSlice(tsigAlgorithms, false),
+									},
+									"key": &schema.Schema{
+										Type:      schema.TypeString,
+										Required:  true,
+										Sensitive: true,
+									},
+								},
+							},
+						},
+						"last_xfr": &schema.Schema{
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"expired": &schema.Schema{
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"error": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"primary_enabled": &schema.Schema{
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"primary", "secondary"},
+			},
+			"secondaries": &schema.Schema{
+				Type:          schema.TypeList,
+				Optional:      true,
+				ConflictsWith: []string{"primary", "secondary"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ip": &schema.Schema{
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.IsIPAddress,
+						},
+						"port": &schema.Schema{
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      53,
+							ValidateFunc: validation.IsPortNumber,
+						},
+						"notify": &schema.Schema{
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						"networks": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeInt},
+						},
+					},
+				},
 			},
 		},
 		Create: ZoneCreate,
@@ -78,7 +180,19 @@ func zoneResource() *schema.Resource {
 	}
 }
 
-func zoneToResourceData(d *schema.ResourceData, z *nsone.Zone) {
+// tsigAlgorithms are the algorithms a TSIG key can use.
+var tsigAlgorithms = []string{"hmac-md5", "hmac-sha1", "hmac-sha256", "hmac-sha512"}
+
+func zoneToResourceData(d *schema.ResourceData, z *Zone) {
+	zoneAttributesToResourceData(d, z)
+	// dns_servers is a comma separated string on the resource, for
+	// backwards compatibility.
+	d.Set("dns_servers", strings.Join(z.Dns_servers[:], ","))
+}
+
+// zoneAttributesToResourceData sets the attributes the zone resource and
+// the nsone_zone data source have in common, i.e. all but dns_servers.
+func zoneAttributesToResourceData(d *schema.ResourceData, z *Zone) {
 	d.SetId(z.Id)
 	d.Set("hostmaster", z.Hostmaster)
 	d.Set("ttl", z.Ttl)
@@ -86,17 +200,37 @@ func zoneToResourceData(d *schema.ResourceData, z *nsone.Zone) {
 	d.Set("refresh", z.Refresh)
 	d.Set("retry", z.Retry)
 	d.Set("expiry", z.Expiry)
-	d.Set("dns_servers", strings.Join(z.Dns_servers[:], ","))
 	d.Set("networks", strings.Join(int2StringSlice(z.Networks)[:], ","))
 	if z.Secondary != nil && z.Secondary.Enabled {
-		d.Set("primary", z.Secondary.Primary_ip)
+		// A zone made secondary with the deprecated primary attribute keeps
+		// using it.
+		if _, ok := d.GetOk("primary"); ok {
+			d.Set("primary", z.Secondary.Primary_ip)
+		} else {
+			d.Set("secondary", []interface{}{zoneSecondaryToMap(d, z.Secondary)})
+		}
+	} else {
+		d.Set("secondary", nil)
+	}
+	if z.Primary != nil {
+		d.Set("primary_enabled", z.Primary.Enabled)
+		secondaries := make([]map[string]interface{}, len(z.Primary.Secondaries))
+		for i, s := range z.Primary.Secondaries {
+			secondaries[i] = map[string]interface{}{
+				"ip":       s.Ip,
+				"port":     s.Port,
+				"notify":   s.Notify,
+				"networks": s.Networks,
+			}
+		}
+		d.Set("secondaries", secondaries)
 	}
 	if z.Link != "" {
 		d.Set("link", z.Link)
 	}
 }
 
-func resourceToZoneData(z *nsone.Zone, d *schema.ResourceData) {
+func resourceToZoneData(z *Zone, d *schema.ResourceData) {
 	z.Id = d.Id()
 	if v, ok := d.GetOk("hostmaster"); ok {
 		z.Hostmaster = v.(string)
@@ -116,8 +250,29 @@ func resourceToZoneData(z *nsone.Zone, d *schema.ResourceData) {
 	if v, ok := d.GetOk("expiry"); ok {
 		z.Expiry = v.(int)
 	}
-	if v, ok := d.GetOk("primary"); ok {
+	if v, ok := d.GetOk("secondary"); ok {
+		z.MakeSecondary("")
+		zoneSecondaryFromMap(z.Secondary, v.([]interface{})[0].(map[string]interface{}))
+	} else if v, ok := d.GetOk("primary"); ok {
 		z.MakeSecondary(v.(string))
+	} else {
+		rawSecondaries := d.Get("secondaries").([]interface{})
+		secondaries := make([]ZoneSecondaryServer, len(rawSecondaries))
+		for i, raw := range rawSecondaries {
+			s := raw.(map[string]interface{})
+			secondaries[i] = ZoneSecondaryServer{
+				Ip:     s["ip"].(string),
+				Port:   s["port"].(int),
+				Notify: s["notify"].(bool),
+			}
+			for _, n := range s["networks"].([]interface{}) {
+				secondaries[i].Networks = append(secondaries[i].Networks, n.(int))
+			}
+		}
+		z.MakePrimary(secondaries...)
+		if v, ok := d.GetOkExists("primary_enabled"); ok {
+			z.Primary.Enabled = v.(bool)
+		}
 	}
 	if v, ok := d.GetOk("link"); ok {
 		z.LinkTo(v.(string))
@@ -129,9 +284,53 @@ func resourceToZoneData(z *nsone.Zone, d *schema.ResourceData) {
 	}
 }
 
+// zoneSecondaryToMap returns the secondary block for a secondary zone's
+// config. If the API does not return the TSIG key, the key in d is kept.
+func zoneSecondaryToMap(d *schema.ResourceData, s *ZoneSecondary) map[string]interface{} {
+	m := map[string]interface{}{
+		"primary_ip":   s.Primary_ip,
+		"primary_port": s.Primary_port,
+		"other_ips":    s.Other_ips,
+		"last_xfr":     s.Last_xfr,
+		"expired":      s.Expired,
+		"error":        s.Error,
+	}
+	if s.Tsig != nil && s.Tsig.Enabled {
+		key := s.Tsig.Key
+		if key == "" {
+			key, _ = d.Get("secondary.0.tsig.0.key").(string)
+		}
+		m["tsig"] = []interface{}{map[string]interface{}{
+			"name":      s.Tsig.Name,
+			"algorithm": s.Tsig.Hash,
+			"key":       key,
+		}}
+	}
+	return m
+}
+
+// zoneSecondaryFromMap sets a secondary zone's config from its secondary
+// block.
+func zoneSecondaryFromMap(s *ZoneSecondary, m map[string]interface{}) {
+	s.Primary_ip = m["primary_ip"].(string)
+	s.Primary_port = m["primary_port"].(int)
+	for _, ip := range m["other_ips"].([]interface{}) {
+		s.Other_ips = append(s.Other_ips, ip.(string))
+	}
+	if tsig, ok := m["tsig"].([]interface{}); ok && len(tsig) > 0 && tsig[0] != nil {
+		t := tsig[0].(map[string]interface{})
+		s.Tsig = &ZoneTsig{
+			Enabled: true,
+			Name:    t["name"].(string),
+			Hash:    t["algorithm"].(string),
+			Key:     t["key"].(string),
+		}
+	}
+}
+
 func ZoneCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
-	z := nsone.NewZone(d.Get("zone").(string))
+	client := meta.(*Client)
+	z := NewZone(d.Get("zone").(string))
 	resourceToZoneData(z, d)
 	if err := client.CreateZone(z); err != nil {
 		return err
@@ -141,25 +340,25 @@ func ZoneCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func ZoneRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	z, err := client.GetZone(d.Get("zone").(string))
 	if err != nil {
-		return err
+		return readError(d, "zone", err)
 	}
 	zoneToResourceData(d, z)
 	return nil
 }
 
 func ZoneDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
+	client := meta.(*Client)
 	err := client.DeleteZone(d.Get("zone").(string))
 	d.SetId("")
 	return err
 }
 
 func ZoneUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*nsone.APIClient)
-	z := nsone.NewZone(d.Get("zone").(string))
+	client := meta.(*Client)
+	z := NewZone(d.Get("zone").(string))
 	resourceToZoneData(z, d)
 	if err := client.UpdateZone(z); err != nil {
 		return err
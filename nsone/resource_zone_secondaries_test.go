@@ -0,0 +1,112 @@
+package nsone
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestZoneSecondaries(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, zoneResource().Schema, map[string]interface{}{
+		"zone":            "terraform.io",
+		"primary_enabled": false,
+		"secondaries": []interface{}{
+			map[string]interface{}{"ip": "192.0.2.1", "notify": true},
+			map[string]interface{}{"ip": "192.0.2.2", "port": 5353, "networks": []interface{}{0, 1}},
+		},
+	})
+	z := NewZone("terraform.io")
+	resourceToZoneData(z, d)
+
+	b, err := json.Marshal(z)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	var sent struct {
+		Zone    string                 `json:"zone"`
+		Primary map[string]interface{} `json:"primary"`
+	}
+	if err := json.Unmarshal(b, &sent); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]interface{}{
+		"enabled": false,
+		"secondaries": []interface{}{
+			map[string]interface{}{"ip": "192.0.2.1", "port": float64(53), "notify": true},
+			map[string]interface{}{"ip": "192.0.2.2", "port": float64(5353), "notify": false, "networks": []interface{}{float64(0), float64(1)}},
+		},
+	}
+	if sent.Zone != "terraform.io" || !reflect.DeepEqual(sent.Primary, expected) {
+		t.Fatalf("bad zone sent: %s", b)
+	}
+
+	// Read back, as from the API.
+	read := NewZone("terraform.io")
+	if err := json.Unmarshal(b, read); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	d = schema.TestResourceDataRaw(t, zoneResource().Schema, map[string]interface{}{"zone": "terraform.io"})
+	zoneToResourceData(d, read)
+	if d.Get("primary_enabled").(bool) {
+		t.Fatalf("expected primary_enabled to be read back as false")
+	}
+	if d.Get("secondaries.1.port").(int) != 5353 || d.Get("secondaries.1.networks.1").(int) != 1 || !d.Get("secondaries.0.notify").(bool) {
+		t.Fatalf("bad secondaries read back: %#v", d.Get("secondaries"))
+	}
+
+	// A secondary zone has no secondaries of its own.
+	d = schema.TestResourceDataRaw(t, zoneResource().Schema, map[string]interface{}{
+		"zone":    "terraform.io",
+		"primary": "192.0.2.53",
+	})
+	z = NewZone("terraform.io")
+	resourceToZoneData(z, d)
+	if z.Primary.Enabled || len(z.Primary.Secondaries) != 0 || z.Secondary == nil || !z.Secondary.Enabled {
+		t.Fatalf("bad secondary zone: %+v %+v", z.Primary, z.Secondary)
+	}
+}
+
+func TestZoneSecondary(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, zoneResource().Schema, map[string]interface{}{
+		"zone": "terraform.io",
+		"secondary": []interface{}{map[string]interface{}{
+			"primary_ip":   "192.0.2.53",
+			"primary_port": 5353,
+			"other_ips":    []interface{}{"192.0.2.54"},
+			"tsig": []interface{}{map[string]interface{}{
+				"name":      "transfer-key",
+				"algorithm": "hmac-sha256",
+				"key":       "c2VjcmV0",
+			}},
+		}},
+	})
+	z := NewZone("terraform.io")
+	resourceToZoneData(z, d)
+	expected := &ZoneSecondary{
+		Enabled:      true,
+		Primary_ip:   "192.0.2.53",
+		Primary_port: 5353,
+		Other_ips:    []string{"192.0.2.54"},
+		Tsig:         &ZoneTsig{Enabled: true, Name: "transfer-key", Hash: "hmac-sha256", Key: "c2VjcmV0"},
+	}
+	if !reflect.DeepEqual(z.Secondary, expected) || z.Primary.Enabled {
+		t.Fatalf("bad secondary zone: %+v %+v", z.Primary, z.Secondary)
+	}
+
+	// The API returns the status of the last transfer, but not the key.
+	z.Secondary.Tsig.Key = ""
+	z.Secondary.Last_xfr = 1500000000
+	z.Secondary.Error = "refused"
+	zoneToResourceData(d, z)
+	if d.Get("secondary.0.tsig.0.key").(string) != "c2VjcmV0" {
+		t.Fatalf("expected the TSIG key to be kept")
+	}
+	if d.Get("secondary.0.last_xfr").(int) != 1500000000 || d.Get("secondary.0.error").(string) != "refused" {
+		t.Fatalf("bad transfer status: %#v", d.Get("secondary"))
+	}
+	if d.Get("primary").(string) != "" {
+		t.Fatalf("expected primary to be left unset")
+	}
+}
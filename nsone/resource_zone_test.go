@@ -6,11 +6,10 @@ import (
 
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
-	nsone "gopkg.in/sarguru/ns1-go.v18"
 )
 
 func TestAccZone_basic(t *testing.T) {
-	var zone nsone.Zone
+	var zone Zone
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
@@ -29,7 +28,7 @@ func TestAccZone_basic(t *testing.T) {
 }
 
 func TestAccZone_updated(t *testing.T) {
-	var zone nsone.Zone
+	var zone Zone
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
@@ -55,6 +54,52 @@ func TestAccZone_updated(t *testing.T) {
 	})
 }
 
+func TestAccZone_secondaries(t *testing.T) {
+	var zone Zone
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckZoneDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccZone_secondaries,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckZoneExists("nsone_zone.foobar", &zone),
+					testAccCheckZoneSecondaries(&zone, "192.0.2.1", "192.0.2.2"),
+					testAccCheckZoneState("secondaries.1.port", "5353"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccZone_secondary(t *testing.T) {
+	var zone Zone
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckZoneDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccZone_secondary("192.0.2.53"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckZoneExists("nsone_zone.foobar", &zone),
+					testAccCheckZoneState("secondary.0.primary_ip", "192.0.2.53"),
+					testAccCheckZoneState("secondary.0.tsig.0.algorithm", "hmac-sha256"),
+				),
+			},
+			resource.TestStep{
+				// Changing the primary updates the zone in place.
+				Config: testAccZone_secondary("192.0.2.54"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckZoneExists("nsone_zone.foobar", &zone),
+					testAccCheckZoneState("secondary.0.primary_ip", "192.0.2.54"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckZoneState(key, value string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources["nsone_zone.foobar"]
@@ -76,7 +121,7 @@ func testAccCheckZoneState(key, value string) resource.TestCheckFunc {
 	}
 }
 
-func testAccCheckZoneExists(n string, zone *nsone.Zone) resource.TestCheckFunc {
+func testAccCheckZoneExists(n string, zone *Zone) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
 
@@ -88,7 +133,7 @@ func testAccCheckZoneExists(n string, zone *nsone.Zone) resource.TestCheckFunc {
 			return fmt.Errorf("NoID is set")
 		}
 
-		client := testAccProvider.Meta().(*nsone.APIClient)
+		client := testAccProvider.Meta().(*Client)
 
 		foundZone, err := client.GetZone(rs.Primary.Attributes["zone"])
 
@@ -109,7 +154,7 @@ func testAccCheckZoneExists(n string, zone *nsone.Zone) resource.TestCheckFunc {
 }
 
 func testAccCheckZoneDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*nsone.APIClient)
+	client := testAccProvider.Meta().(*Client)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "nsone_zone" {
@@ -126,7 +171,7 @@ func testAccCheckZoneDestroy(s *terraform.State) error {
 	return nil
 }
 
-func testAccCheckZoneAttributes(zone *nsone.Zone) resource.TestCheckFunc {
+func testAccCheckZoneAttributes(zone *Zone) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		if zone.Ttl != 3600 {
 			return fmt.Errorf("Bad value : %d", zone.Ttl)
@@ -140,7 +185,7 @@ func testAccCheckZoneAttributes(zone *nsone.Zone) resource.TestCheckFunc {
 	}
 }
 
-func testAccCheckZoneAttributesUpdated(zone *nsone.Zone) resource.TestCheckFunc {
+func testAccCheckZoneAttributesUpdated(zone *Zone) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		if zone.Ttl != 3601 {
 			return fmt.Errorf("Bad value : %d", zone.Ttl)
@@ -154,6 +199,23 @@ func testAccCheckZoneAttributesUpdated(zone *nsone.Zone) resource.TestCheckFunc
 	}
 }
 
+func testAccCheckZoneSecondaries(zone *Zone, ips ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if zone.Primary == nil || !zone.Primary.Enabled {
+			return fmt.Errorf("Bad primary : %+v", zone.Primary)
+		}
+		if len(zone.Primary.Secondaries) != len(ips) {
+			return fmt.Errorf("Bad number of secondaries : %d", len(zone.Primary.Secondaries))
+		}
+		for i, ip := range ips {
+			if zone.Primary.Secondaries[i].Ip != ip {
+				return fmt.Errorf("Bad secondary %d : %+v", i, zone.Primary.Secondaries[i])
+			}
+		}
+		return nil
+	}
+}
+
 const testAccZone_basic = `
 resource "nsone_zone" "foobar" {
 	zone = "terraform.io"
@@ -169,3 +231,35 @@ resource "nsone_zone" "foobar" {
 	ttl = "3601"
 	nx_ttl = "3601"
 }`
+
+const testAccZone_secondaries = `
+resource "nsone_zone" "foobar" {
+	zone = "terraform.io"
+	primary_enabled = true
+	secondaries {
+		ip = "192.0.2.1"
+		notify = true
+	}
+	secondaries {
+		ip = "192.0.2.2"
+		port = 5353
+		networks = [0]
+	}
+}`
+
+func testAccZone_secondary(primary string) string {
+	return fmt.Sprintf(`
+resource "nsone_zone" "foobar" {
+	zone = "terraform.io"
+	secondary {
+		primary_ip = "%s"
+		primary_port = 5353
+		other_ips = ["192.0.2.55"]
+		tsig {
+			name = "terraform-key"
+			algorithm = "hmac-sha256"
+			key = "c2VjcmV0a2V5"
+		}
+	}
+}`, primary)
+}
@@ -0,0 +1,69 @@
+package nsone
+
+import (
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// retryBaseWait is the wait before the first retry; it doubles on each
+// further attempt, up to the configured maximum.
+const retryBaseWait = 1 * time.Second
+
+// shouldRetry decides whether a failed request is worth sending again.
+// header is nil when no response was received at all.
+//
+// A 429 means the API did not act on the request, so any request is
+// retried. Server errors and dropped connections may happen after the API
+// has acted, so those are only retried for reads, deletes and updates,
+// which are idempotent; creates (PUT) could otherwise be applied twice,
+// and are only retried if the connection could not be made in the first
+// place. A retried delete that finds the object gone is taken to have
+// succeeded, see doHTTP.
+func shouldRetry(method string, status int, header http.Header, err error) bool {
+	if err == nil {
+		return false
+	}
+	if header == nil {
+		if method != "PUT" {
+			return true
+		}
+		var opErr *net.OpError
+		return errors.As(err, &opErr) && opErr.Op == "dial"
+	}
+	switch {
+	case status == http.StatusTooManyRequests:
+		return true
+	case status >= 500 && status != http.StatusNotImplemented:
+		return method != "PUT"
+	}
+	return false
+}
+
+// retryWait returns how long to wait before the given retry attempt (1 for
+// the first retry): the API's Retry-After if it sent one, otherwise an
+// exponential backoff with jitter, never more than maxWait.
+func retryWait(attempt int, maxWait time.Duration, header http.Header) time.Duration {
+	if header != nil {
+		if s, err := strconv.Atoi(header.Get("Retry-After")); err == nil && s >= 0 {
+			return minDuration(time.Duration(s)*time.Second, maxWait)
+		}
+	}
+	wait := retryBaseWait << uint(attempt-1)
+	if wait <= 0 || wait > maxWait {
+		wait = maxWait
+	}
+	// Spread retries from parallel resource operations out a little.
+	jitter := time.Duration(rand.Int63n(int64(wait)/4 + 1))
+	return minDuration(wait/2+wait/4+jitter, maxWait)
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
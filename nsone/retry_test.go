@@ -0,0 +1,133 @@
+package nsone
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	nsone "gopkg.in/sarguru/ns1-go.v18"
+)
+
+// testRetryServer fails the first failures requests with status, then
+// answers with an empty JSON object. It returns the server and a pointer to
+// the number of requests it has seen.
+func testRetryServer(status int, failures int) (*httptest.Server, *int) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests <= failures {
+			w.WriteHeader(status)
+			w.Write([]byte(`{"message":"try again"}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	return ts, &requests
+}
+
+func TestClient_retryRead(t *testing.T) {
+	ts, requests := testRetryServer(http.StatusBadGateway, 2)
+	defer ts.Close()
+
+	client := NewClient("xxx", ts.URL, nil)
+	client.SetRetry(3, 10*time.Millisecond)
+	if _, err := client.GetZone("example.com"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if *requests != 3 {
+		t.Fatalf("expected 3 requests, got %d", *requests)
+	}
+}
+
+func TestClient_retryGivesUp(t *testing.T) {
+	ts, requests := testRetryServer(http.StatusServiceUnavailable, 10)
+	defer ts.Close()
+
+	client := NewClient("xxx", ts.URL, nil)
+	client.SetRetry(2, 10*time.Millisecond)
+	_, err := client.GetZone("example.com")
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Fatalf("error should carry the attempt count: %s", err)
+	}
+	if *requests != 3 {
+		t.Fatalf("expected 3 requests, got %d", *requests)
+	}
+}
+
+func TestClient_retryCreate(t *testing.T) {
+	// A create that hit a server error may have been applied, so is not
+	// retried...
+	ts, requests := testRetryServer(http.StatusInternalServerError, 1)
+	defer ts.Close()
+
+	client := NewClient("xxx", ts.URL, nil)
+	client.SetRetry(3, 10*time.Millisecond)
+	if err := client.CreateDataSource(nsone.NewDataSource("test", "nsone_v1")); err == nil {
+		t.Fatalf("expected error")
+	}
+	if *requests != 1 {
+		t.Fatalf("expected 1 request, got %d", *requests)
+	}
+
+	// ...but a rate limited one was not, so is.
+	ts, requests = testRetryServer(http.StatusTooManyRequests, 1)
+	defer ts.Close()
+
+	client = NewClient("xxx", ts.URL, nil)
+	client.SetRetry(3, 10*time.Millisecond)
+	if err := client.CreateDataSource(nsone.NewDataSource("test", "nsone_v1")); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if *requests != 2 {
+		t.Fatalf("expected 2 requests, got %d", *requests)
+	}
+}
+
+func TestClient_retryDelete(t *testing.T) {
+	// The first delete fails after it was applied, so the retry finds
+	// nothing to delete.
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient("xxx", ts.URL, nil)
+	client.SetRetry(3, 10*time.Millisecond)
+	if err := client.DeleteZone("example.com"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if requests != 2 {
+		t.Fatalf("expected 2 requests, got %d", requests)
+	}
+
+	// A delete that is not found on its first attempt is still an error.
+	requests = 1
+	if err := client.DeleteZone("example.com"); !isNotFound(err) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+}
+
+func TestRetryWait(t *testing.T) {
+	h := http.Header{}
+	h.Set("Retry-After", "2")
+	if wait := retryWait(1, time.Minute, h); wait != 2*time.Second {
+		t.Fatalf("Retry-After not honoured: %s", wait)
+	}
+	for attempt := 1; attempt < 10; attempt++ {
+		if wait := retryWait(attempt, 5*time.Second, nil); wait > 5*time.Second {
+			t.Fatalf("attempt %d waited %s, more than retry_max_wait", attempt, wait)
+		}
+	}
+}
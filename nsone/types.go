@@ -0,0 +1,137 @@
+package nsone
+
+import (
+	nsone "gopkg.in/sarguru/ns1-go.v18"
+)
+
+// Record is an nsone.Record whose metadata, and that of its regions, can
+// hold typed values and feed pointers, which nsone.Record's
+// map[string]string and nsone.RegionMeta cannot. Meta and Regions are
+// always sent, so that metadata or regions removed from the configuration
+// are removed from the record too.
+type Record struct {
+	nsone.Record
+	Meta    map[string]interface{} `json:"meta"`
+	Regions map[string]Region      `json:"regions"`
+}
+
+// Region is a named group of a record's answers, with metadata shared by
+// all the answers in it.
+type Region struct {
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// NewRecord takes a zone, domain and record type t and creates a *Record
+// with no metadata or regions.
+func NewRecord(zone string, domain string, t string) *Record {
+	return &Record{
+		Record:  *nsone.NewRecord(zone, domain, t),
+		Meta:    make(map[string]interface{}),
+		Regions: make(map[string]Region),
+	}
+}
+
+// LinkTo makes the record a linked record, answering with the answers of
+// the record at domain to.
+func (r *Record) LinkTo(to string) {
+	r.Record.LinkTo(to)
+	r.Meta = make(map[string]interface{})
+}
+
+// Zone is an nsone.Zone whose primary config lists secondaries with the
+// networks they are notified on, and whose secondary config can have more
+// than one primary and a TSIG key, which nsone.ZonePrimary and
+// nsone.ZoneSecondary cannot.
+type Zone struct {
+	nsone.Zone
+	Primary   *ZonePrimary   `json:"primary,omitempty"`
+	Secondary *ZoneSecondary `json:"secondary,omitempty"`
+}
+
+// ZonePrimary is a zone's primary config: whether it allows zone transfers,
+// and the secondaries it allows them to.
+type ZonePrimary struct {
+	Enabled     bool                  `json:"enabled"`
+	Secondaries []ZoneSecondaryServer `json:"secondaries"`
+}
+
+// ZoneSecondaryServer is a secondary allowed to transfer a zone, notified
+// of changes to it if Notify is set.
+type ZoneSecondaryServer struct {
+	Ip       string `json:"ip"`
+	Port     int    `json:"port,omitempty"`
+	Notify   bool   `json:"notify"`
+	Networks []int  `json:"networks,omitempty"`
+}
+
+// ZoneSecondary is a secondary zone's config: the primaries it is
+// transferred from, the TSIG key transfers are signed with, and the status
+// of the last transfer.
+type ZoneSecondary struct {
+	Enabled      bool      `json:"enabled"`
+	Primary_ip   string    `json:"primary_ip,omitempty"`
+	Primary_port int       `json:"primary_port,omitempty"`
+	Other_ips    []string  `json:"other_ips,omitempty"`
+	Tsig         *ZoneTsig `json:"tsig,omitempty"`
+	Last_xfr     int       `json:"last_xfr,omitempty"`
+	Expired      bool      `json:"expired,omitempty"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// ZoneTsig is the TSIG key a secondary zone's transfers are signed with.
+// Hash is the key's algorithm, e.g. hmac-sha256.
+type ZoneTsig struct {
+	Enabled bool   `json:"enabled"`
+	Name    string `json:"name,omitempty"`
+	Hash    string `json:"hash,omitempty"`
+	Key     string `json:"key,omitempty"`
+}
+
+// NewZone takes a zone domain name and creates a *Zone that is a primary
+// with no secondaries.
+func NewZone(zone string) *Zone {
+	z := &Zone{Zone: nsone.Zone{Zone: zone}}
+	z.MakePrimary()
+	return z
+}
+
+// MakePrimary makes the zone a primary, allowing transfers to secondaries.
+func (z *Zone) MakePrimary(secondaries ...ZoneSecondaryServer) {
+	z.Secondary = nil
+	if secondaries == nil {
+		secondaries = make([]ZoneSecondaryServer, 0)
+	}
+	z.Primary = &ZonePrimary{
+		Enabled:     true,
+		Secondaries: secondaries,
+	}
+}
+
+// MakeSecondary makes the zone a secondary, transferred from the primary
+// at ip on port 53.
+func (z *Zone) MakeSecondary(ip string) {
+	z.Secondary = &ZoneSecondary{
+		Enabled:      true,
+		Primary_ip:   ip,
+		Primary_port: 53,
+	}
+	z.Primary = &ZonePrimary{
+		Enabled:     false,
+		Secondaries: make([]ZoneSecondaryServer, 0),
+	}
+}
+
+// LinkTo makes the zone a linked zone, serving the records of the zone to.
+func (z *Zone) LinkTo(to string) {
+	z.Zone.LinkTo(to)
+	z.Primary = nil
+	z.Secondary = nil
+}
+
+// TsigKey is a TSIG key, used to sign zone transfers. Algorithm is one of
+// tsigAlgorithms and Secret is base64 encoded.
+type TsigKey struct {
+	Name      string `json:"name"`
+	Algorithm string `json:"algorithm"`
+	Secret    string `json:"secret"`
+}